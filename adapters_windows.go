@@ -0,0 +1,38 @@
+package gonet
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// gaaFlagIncludeGateways makes GetAdaptersAddresses report the gateways
+// of each adapter. x/sys/windows doesn't define it.
+const gaaFlagIncludeGateways = 0x80
+
+// getAdapterAddresses returns the adapters of the system that are up,
+// along with their gateways and DNS servers.
+func getAdapterAddresses() ([]*windows.IpAdapterAddresses, error) {
+	// GetAdaptersAddresses reports the size it needs when buf is too small
+	size := uint32(15 << 10)
+	for {
+		buf := make([]byte, size)
+		first := (*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0]))
+		err := windows.GetAdaptersAddresses(windows.AF_UNSPEC, gaaFlagIncludeGateways, 0, first, &size)
+		if err == windows.ERROR_BUFFER_OVERFLOW {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		var adapters []*windows.IpAdapterAddresses
+		for a := first; a != nil; a = a.Next {
+			if a.OperStatus == windows.IfOperStatusUp {
+				adapters = append(adapters, a)
+			}
+		}
+		return adapters, nil
+	}
+}
@@ -0,0 +1,16 @@
+package gonet
+
+import "time"
+
+// Battery holds the status of a battery.
+type Battery struct {
+	Name    string  `json:"name" yaml:"name"`
+	Percent float64 `json:"percent" yaml:"percent"`
+
+	// State is one of Charging, Discharging, Full, Not charging or Unknown.
+	State string `json:"state" yaml:"state"`
+
+	// TimeRemaining is the time until the battery is empty when discharging,
+	// or full when charging. It is zero when unknown.
+	TimeRemaining time.Duration `json:"time_remaining" yaml:"time_remaining"`
+}
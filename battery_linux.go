@@ -0,0 +1,66 @@
+//go:build linux
+
+package gonet
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const powerSupplyPath = "/sys/class/power_supply"
+
+// getBatteries returns the status of every battery under /sys/class/power_supply.
+// Machines without a battery have none and give no error.
+func getBatteries() ([]Battery, error) {
+	entries, err := os.ReadDir(powerSupplyPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var batteries []Battery
+	for _, entry := range entries {
+		dir := filepath.Join(powerSupplyPath, entry.Name())
+		if readSysString(dir, "type") != "Battery" {
+			continue
+		}
+
+		b := Battery{
+			Name:  entry.Name(),
+			State: readSysString(dir, "status"),
+		}
+
+		if b.State == "" {
+			b.State = "Unknown"
+		}
+
+		// batteries report either energy (µWh, µW) or charge (µAh, µA)
+		now, full, rate := readSysUint(dir, "energy_now"), readSysUint(dir, "energy_full"), readSysUint(dir, "power_now")
+		if full == 0 {
+			now, full, rate = readSysUint(dir, "charge_now"), readSysUint(dir, "charge_full"), readSysUint(dir, "current_now")
+		}
+
+		if capacity, ok := readSysFloat(dir, "capacity"); ok {
+			b.Percent = capacity
+		} else if full > 0 {
+			b.Percent = float64(now) / float64(full) * 100
+		}
+
+		if rate > 0 {
+			switch b.State {
+			case "Discharging":
+				b.TimeRemaining = time.Duration(float64(now) / float64(rate) * float64(time.Hour))
+			case "Charging":
+				if full > now {
+					b.TimeRemaining = time.Duration(float64(full-now) / float64(rate) * float64(time.Hour))
+				}
+			}
+		}
+		batteries = append(batteries, b)
+	}
+	return batteries, nil
+}
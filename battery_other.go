@@ -0,0 +1,8 @@
+//go:build !linux
+
+package gonet
+
+// getBatteries is only implemented on linux.
+func getBatteries() ([]Battery, error) {
+	return nil, nil
+}
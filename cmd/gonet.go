@@ -1,11 +1,98 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
 	"os"
 
 	"github.com/abiiranathan/gonet"
 )
 
 func main() {
-	gonet.WriteMetrics(os.Stdout)
+	formatName := flag.String("format", "table", "output `format`: table, json, csv, yaml, markdown, html, prometheus, kv or influx")
+	jsonOutput := flag.Bool("json", false, "write metrics as JSON, same as -format json")
+	redact := flag.Bool("redact", false, "mask MAC and IP addresses, the hostname and serial number, e.g. for bug reports")
+	pid := flag.Int("pid", 0, "write the metrics of the process with this `pid` only")
+	since := flag.String("since", "", "write network and disk rates since the previous run, saved in this state `file`, as tables or JSON")
+
+	var t gonet.Thresholds
+	check := flag.Bool("check", false, "check the thresholds and exit with a Nagios status code")
+	quiet := flag.Bool("quiet", false, "write only the sections above the thresholds")
+	flag.Float64Var(&t.CPUPercent, "cpu", 0, "critical cpu usage `percent` for -check and -quiet")
+	flag.Float64Var(&t.MemoryPercent, "memory", 0, "critical memory usage `percent` for -check and -quiet")
+	flag.Float64Var(&t.DiskPercent, "disk", 0, "critical disk usage `percent` for -check and -quiet")
+	flag.Float64Var(&t.CPUWarningPercent, "cpu-warning", 0, "warning cpu usage `percent` for -check and -quiet")
+	flag.Float64Var(&t.MemoryWarningPercent, "memory-warning", 0, "warning memory usage `percent` for -check and -quiet")
+	flag.Float64Var(&t.DiskWarningPercent, "disk-warning", 0, "warning disk usage `percent` for -check and -quiet")
+	flag.Parse()
+
+	if *check {
+		os.Exit(gonet.RunCheck(t))
+	}
+
+	if *pid > 0 {
+		if err := gonet.WriteProcessMetrics(os.Stdout, int32(*pid)); err != nil {
+			fmt.Fprintln(os.Stderr, "gonet:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	format, err := gonet.ParseFormat(*formatName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *jsonOutput {
+		format = gonet.FormatJSON
+	}
+	if *since != "" && format != gonet.FormatTable && format != gonet.FormatJSON {
+		fmt.Fprintf(os.Stderr, "gonet: -since writes tables or JSON, not %s\n", format)
+		os.Exit(2)
+	}
+
+	opts := []gonet.Option{gonet.WithThresholds(t), gonet.WithQuiet(*quiet)}
+	if *redact {
+		opts = append(opts, gonet.WithRedaction(gonet.RedactMAC|gonet.RedactIP|gonet.RedactHostname|gonet.RedactSerial))
+	}
+
+	write := func() error { return gonet.WriteMetricsAs(os.Stdout, format, opts...) }
+	if *since != "" {
+		write = func() error { return writeRatesSince(*since, format, opts) }
+	}
+
+	// subsystems that could not be read are reported on stderr,
+	// the metrics that could be read are still written
+	if err := write(); err != nil {
+		fmt.Fprintln(os.Stderr, "gonet:", err)
+
+		var collectErr *gonet.CollectError
+		if !errors.As(err, &collectErr) {
+			os.Exit(1)
+		}
+	}
+}
+
+// writeRatesSince writes the metrics with the rates since the previous run,
+// saved in the state file, as tables or JSON. It returns the write error,
+// otherwise the error of reading the metrics or saving the state.
+func writeRatesSince(statePath string, format gonet.Format, opts []gonet.Option) error {
+	rates, err := gonet.ReadRatesAgainstFile(statePath, opts...)
+
+	var werr error
+	if format == gonet.FormatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		werr = enc.Encode(rates)
+	} else {
+		// the rate columns are left out on the first run, which has no rates
+		werr = gonet.RenderMetrics(os.Stdout, rates.Metrics, opts...)
+	}
+
+	if werr != nil {
+		return werr
+	}
+	return err
 }
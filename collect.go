@@ -0,0 +1,446 @@
+package gonet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// Collector reads the metrics of a subsystem, e.g. SubsystemDisk, into m.
+// ReadMetrics calls Collect concurrently for every subsystem listed by
+// Subsystems, so Collect must only set the fields of m that the subsystem
+// owns. An error is reported as a *CollectError for the subsystem.
+// Optional subsystems are only collected when their option is set, e.g.
+// SubsystemDiskIO WithDiskIO and SubsystemProcesses unless
+// WithTopProcesses(0) is set without WithProcessFilter.
+//
+// The default Collector reads the system with gopsutil. Another one can be
+// set with WithCollector, e.g. to test code built on gonet against canned
+// metrics.
+type Collector interface {
+	Collect(ctx context.Context, subsystem string, m *Metrics) error
+}
+
+// NewSystemCollector returns the Collector used by ReadMetrics by default,
+// reading the metrics of this system as configured by opts. It can be
+// wrapped by a Collector that only replaces some subsystems.
+func NewSystemCollector(opts ...Option) Collector {
+	return systemCollector{newOptions(opts)}
+}
+
+// systemCollector reads the metrics of this system.
+type systemCollector struct {
+	o *options
+}
+
+func (c systemCollector) Collect(ctx context.Context, subsystem string, m *Metrics) error {
+	for _, sc := range subsystemCollectors {
+		if sc.subsystem == subsystem {
+			if !c.o.enabled(subsystem) {
+				return nil
+			}
+			return sc.collect(ctx, c.o, m)
+		}
+	}
+	return fmt.Errorf("gonet: unknown subsystem %q", subsystem)
+}
+
+// Subsystems returns every subsystem read by ReadMetrics.
+func Subsystems() []string {
+	subsystems := make([]string, len(subsystemCollectors))
+	for i, sc := range subsystemCollectors {
+		subsystems[i] = sc.subsystem
+	}
+	return subsystems
+}
+
+// enabled reports whether subsystem is read with the options o.
+// The optional subsystems are only read when their option is set.
+func (o *options) enabled(subsystem string) bool {
+	switch subsystem {
+	case SubsystemDiskIO:
+		return o.diskIO
+	case SubsystemCPUTimes:
+		return o.cpuTimesInterval > 0
+	case SubsystemConnections:
+		return o.connections
+	case SubsystemGPU:
+		return o.gpu
+	case SubsystemProcesses:
+		return o.topProcesses > 0 || o.processFilter != nil
+	case SubsystemProcessNetIO:
+		return o.processNetIO
+	default:
+		return true
+	}
+}
+
+// subsystemCollector reads a single subsystem into the metrics.
+// It must only set the fields of m that it owns.
+type subsystemCollector struct {
+	subsystem string
+	collect   func(ctx context.Context, o *options, m *Metrics) error
+}
+
+// subsystemCollectors are run concurrently by ReadMetricsContext.
+var subsystemCollectors = []subsystemCollector{
+	{SubsystemDisk, collectDisk},
+	{SubsystemPartitions, collectPartitions},
+	{SubsystemDiskIO, collectDiskIO},
+	{SubsystemMemory, collectMemory},
+	{SubsystemContainer, collectContainer},
+	{SubsystemCPU, collectCPUInfo},
+	{SubsystemCPUPercent, collectCPUPercent},
+	{SubsystemCPUCounts, collectCPUCounts},
+	{SubsystemCPUTimes, collectCPUTimes},
+	{SubsystemPerCorePercent, collectPerCorePercent},
+	{SubsystemHost, collectHost},
+	{SubsystemHardware, collectHardware},
+	{SubsystemLoad, collectLoad},
+	{SubsystemNetwork, collectNetwork},
+	{SubsystemNetIO, collectNetIO},
+	{SubsystemGateway, collectGateway},
+	{SubsystemDNS, collectDNS},
+	{SubsystemConnections, collectConnections},
+	{SubsystemTemperatures, collectTemperatures},
+	{SubsystemGPU, collectGPUs},
+	{SubsystemBattery, collectBatteries},
+	{SubsystemProcesses, collectProcesses},
+	{SubsystemFileDescriptors, collectFileDescriptors},
+	{SubsystemProcessNetIO, collectProcessNetIO},
+}
+
+func collectDisk(ctx context.Context, o *options, m *Metrics) error {
+	m.DiskPath = o.diskPath
+	du, err := withContext(ctx, func(ctx context.Context) (*disk.UsageStat, error) {
+		return getDiskUsage(ctx, o.diskPath)
+	})
+	if err != nil {
+		return err
+	}
+
+	if o.raw != nil {
+		o.raw.RawDiskUsage = du
+	}
+
+	// Free is Total-Used, so it includes blocks reserved for root.
+	m.DiskSize = du.Total
+	m.DiskUsage = du.Used
+	m.DiskFree = du.Total - du.Used
+	m.DiskUsedPercent = percent(du.Used, du.Total)
+	return nil
+}
+
+func collectPartitions(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.Disks, err = getPartitionsUsage(ctx, o.pseudoFilesystems)
+	return err
+}
+
+func collectDiskIO(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.DiskIO, err = getDiskIO(ctx, o.diskRateInterval)
+	return err
+}
+
+func collectMemory(ctx context.Context, o *options, m *Metrics) error {
+	vmStat, err := withContext(ctx, mem.VirtualMemoryWithContext)
+	if err != nil {
+		return err
+	}
+
+	if o.raw != nil {
+		o.raw.RawVirtualMemory = vmStat
+	}
+
+	m.TotalMemory = vmStat.Total
+	m.FreeMemory = vmStat.Free
+	m.UsedMemory = vmStat.Used
+	m.CacheMemory = vmStat.Cached
+	m.MemoryUsedPercent = vmStat.UsedPercent
+	return nil
+}
+
+func collectContainer(ctx context.Context, o *options, m *Metrics) error {
+	type limits struct {
+		inContainer bool
+		memoryLimit uint64
+		cpus        float64
+	}
+
+	l, err := withContext(ctx, func(context.Context) (limits, error) {
+		var l limits
+		l.inContainer, l.memoryLimit, l.cpus = getContainerLimits()
+		return l, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	m.InContainer, m.ContainerMemLimit, m.ContainerCPUQuota = l.inContainer, l.memoryLimit, l.cpus
+	return nil
+}
+
+func collectCPUInfo(ctx context.Context, o *options, m *Metrics) error {
+	cpuStats, err := withContext(ctx, cpu.InfoWithContext)
+	if err != nil {
+		return err
+	}
+
+	if o.raw != nil {
+		o.raw.RawCPUs = cpuStats
+	}
+
+	// loop through all available cpus
+	coreTypes := getCoreTypes()
+	currentMHz, maxMHz := getCPUFrequencies()
+	for index, c := range cpuStats {
+		m.CPUInfo = append(m.CPUInfo, CPUInfo{
+			Index:      index,
+			VendorID:   c.VendorID,
+			Family:     c.Family,
+			Cores:      int(c.Cores),
+			Model:      c.ModelName,
+			Speed:      strconv.FormatFloat(c.Mhz, 'f', 2, 64) + " MHz",
+			CoreType:   coreTypes[int(c.CPU)],
+			CurrentMHz: currentMHz[int(c.CPU)],
+			MaxMHz:     maxMHz[int(c.CPU)],
+		})
+	}
+	return nil
+}
+
+func collectCPUCounts(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.LogicalCores, err = withContext(ctx, func(ctx context.Context) (int, error) {
+		return cpu.CountsWithContext(ctx, true)
+	})
+	if err != nil {
+		return err
+	}
+
+	m.PhysicalCores, err = withContext(ctx, func(ctx context.Context) (int, error) {
+		return cpu.CountsWithContext(ctx, false)
+	})
+	return err
+}
+
+func collectCPUPercent(ctx context.Context, o *options, m *Metrics) error {
+	percentage, err := withContext(ctx, func(ctx context.Context) ([]float64, error) {
+		return cpu.PercentWithContext(ctx, o.cpuInterval, false)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(percentage) == 0 {
+		return errors.New("no cpu percentage returned")
+	}
+
+	m.CPUPercent = percentage[0]
+	return nil
+}
+
+func collectCPUTimes(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.CPUTimes, err = getCPUTimes(ctx, o.cpuTimesInterval)
+	return err
+}
+
+func collectPerCorePercent(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.PerCorePercent, err = withContext(ctx, func(ctx context.Context) ([]float64, error) {
+		return cpu.PercentWithContext(ctx, o.cpuInterval, true)
+	})
+	return err
+}
+
+func collectHost(ctx context.Context, o *options, m *Metrics) error {
+	hostStat, err := withContext(ctx, host.InfoWithContext)
+	if err != nil {
+		return err
+	}
+
+	if o.raw != nil {
+		o.raw.RawHost = hostStat
+	}
+
+	m.Hostname = hostStat.Hostname
+	m.RunningProcesses = hostStat.Procs
+	m.Platform = hostStat.Platform
+	m.PlatformVersion = hostStat.PlatformVersion
+	m.VirtSystem = hostStat.VirtualizationSystem
+	m.VirtRole = hostStat.VirtualizationRole
+	m.Uptime = time.Duration(hostStat.Uptime) * time.Second
+	m.BootTime = time.Unix(int64(hostStat.BootTime), 0)
+	return nil
+}
+
+func collectHardware(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.Hardware, err = withContext(ctx, func(context.Context) (Hardware, error) {
+		return getHardware(), nil
+	})
+	return err
+}
+
+func collectLoad(ctx context.Context, o *options, m *Metrics) error {
+	// load average is not available on windows
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	avg, err := withContext(ctx, load.AvgWithContext)
+	if isNotImplemented(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if o.raw != nil {
+		o.raw.RawLoad = avg
+	}
+
+	m.LoadAvg = LoadAvg{Load1: avg.Load1, Load5: avg.Load5, Load15: avg.Load15}
+	return nil
+}
+
+func collectNetwork(ctx context.Context, o *options, m *Metrics) error {
+	inetfStat, err := withContext(ctx, net.InterfacesWithContext)
+	if err != nil {
+		return err
+	}
+
+	if o.raw != nil {
+		o.raw.RawInterfaces = inetfStat
+	}
+
+	for _, iface := range inetfStat {
+		if o.interfaceFilter.skip(iface) {
+			continue
+		}
+
+		if iface.MTU > 0 {
+			m.MTUs[iface.Name] = iface.MTU
+		}
+		// iface is reused by the next iteration while an abandoned read may still run
+		name := iface.Name
+		speed, err := withContext(ctx, func(context.Context) (int, error) {
+			return getLinkSpeed(name), nil
+		})
+		if err != nil {
+			return err
+		}
+		if speed > 0 {
+			m.LinkSpeeds[iface.Name] = speed
+		}
+
+		if iface.HardwareAddr != "" {
+			if m.MacAddr == "" {
+				m.MacAddr = iface.HardwareAddr
+			}
+			m.MacAddrs[iface.Name] = iface.HardwareAddr
+		}
+
+		for _, addr := range iface.Addrs {
+			m.IPAddrs[iface.Name] = append(m.IPAddrs[iface.Name], addr.Addr)
+
+			if isIPv4(addr.Addr) {
+				m.IPv4Addrs[iface.Name] = append(m.IPv4Addrs[iface.Name], addr.Addr)
+			} else {
+				m.IPv6Addrs[iface.Name] = append(m.IPv6Addrs[iface.Name], addr.Addr)
+			}
+		}
+	}
+
+	// addresses are sorted so that snapshots compare and render the same
+	for _, addrs := range []map[string][]string{m.IPAddrs, m.IPv4Addrs, m.IPv6Addrs} {
+		for _, a := range addrs {
+			sortAddrs(a)
+		}
+	}
+	return nil
+}
+
+func collectNetIO(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.NetIO, err = getNetIO(ctx, o.netRateInterval, o.interfaceFilter)
+	for _, c := range m.NetIO {
+		m.TotalBytesSent += c.BytesSent
+		m.TotalBytesRecv += c.BytesRecv
+	}
+	return err
+}
+
+func collectGateway(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.PrimaryIP = getPrimaryIP(ctx)
+	m.DefaultGateway, err = withContext(ctx, getDefaultGateway)
+	return err
+}
+
+func collectDNS(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.DNSServers, err = withContext(ctx, func(context.Context) ([]string, error) {
+		return getDNSServers()
+	})
+	return err
+}
+
+func collectConnections(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.Connections, err = getConnections(ctx)
+	return err
+}
+
+func collectTemperatures(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.Temperatures, err = getTemperatures(ctx)
+	return err
+}
+
+func collectGPUs(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.GPUs, err = getGPUs(ctx)
+	return err
+}
+
+func collectBatteries(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.Batteries, err = withContext(ctx, func(context.Context) ([]Battery, error) {
+		return getBatteries()
+	})
+	return err
+}
+
+func collectProcesses(ctx context.Context, o *options, m *Metrics) error {
+	procs, err := withContext(ctx, getProcesses)
+	if err != nil {
+		return err
+	}
+
+	if o.topProcesses > 0 {
+		m.TopCPUProcesses = topProcesses(procs, o.topProcesses, func(p ProcessInfo) float64 { return p.CPUPercent })
+		m.TopMemoryProcesses = topProcesses(procs, o.topProcesses, func(p ProcessInfo) float64 { return float64(p.RSS) })
+	}
+
+	if o.processFilter != nil {
+		m.MatchedProcesses, m.MatchedProcessesTotal = matchProcesses(procs, o.processFilter)
+	}
+	return nil
+}
+
+func collectFileDescriptors(ctx context.Context, o *options, m *Metrics) error {
+	fds, err := withContext(ctx, func(context.Context) ([2]uint64, error) {
+		open, max, err := getFileDescriptors()
+		return [2]uint64{open, max}, err
+	})
+	m.OpenFDs, m.MaxFDs = fds[0], fds[1]
+	return err
+}
+
+func collectProcessNetIO(ctx context.Context, o *options, m *Metrics) (err error) {
+	m.ProcessNetIO, err = withContext(ctx, func(ctx context.Context) ([]ProcessNetIO, error) {
+		return getProcessNetIO(ctx, o.netRateInterval)
+	})
+	return err
+}
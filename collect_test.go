@@ -0,0 +1,106 @@
+package gonet
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// allOptions turns on every optional subsystem, so that all the collectors
+// write into the shared Metrics at once. Run with go test -race.
+var allOptions = []Option{
+	WithCPUInterval(10 * time.Millisecond),
+	WithCPUTimesInterval(10 * time.Millisecond),
+	WithNetRateInterval(10 * time.Millisecond),
+	WithDiskRateInterval(10 * time.Millisecond),
+	WithDiskIO(true),
+	WithConnections(true),
+	WithProcessNetIO(true),
+	WithGPU(true),
+}
+
+func TestReadMetricsConcurrentCollectors(t *testing.T) {
+	raw, err := ReadMetricsRaw(allOptions...)
+	if err != nil {
+		// subsystems may be missing in the test environment
+		t.Logf("ReadMetricsRaw: %v", err)
+	}
+
+	m := raw.Metrics
+	if m.SchemaVersion != SchemaVersion {
+		t.Errorf("SchemaVersion = %q, want %q", m.SchemaVersion, SchemaVersion)
+	}
+
+	if m.GoNumCPU != runtime.NumCPU() {
+		t.Errorf("GoNumCPU = %d, want %d", m.GoNumCPU, runtime.NumCPU())
+	}
+
+	failed := failedSubsystems(err)
+	for subsystem := range m.Errors {
+		if !failed[subsystem] {
+			t.Errorf("error of %s is not returned as a *CollectError", subsystem)
+		}
+	}
+}
+
+func TestReadMetricsContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	m, err := ReadMetricsContext(ctx, allOptions...)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+
+	if m.SchemaVersion != SchemaVersion {
+		t.Errorf("SchemaVersion = %q, want %q", m.SchemaVersion, SchemaVersion)
+	}
+}
+
+func TestReadMetricsContextDeadline(t *testing.T) {
+	// the cpu interval alone outlasts the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := ReadMetricsContext(ctx, WithCPUInterval(5*time.Second))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("ReadMetricsContext returned after %s, past its deadline", elapsed)
+	}
+}
+
+// BenchmarkReadMetrics compares reading the subsystems concurrently, as
+// ReadMetrics does, with reading them one after the other.
+func BenchmarkReadMetrics(b *testing.B) {
+	opts := []Option{WithCPUInterval(10 * time.Millisecond), WithDiskIO(true)}
+
+	b.Run("concurrent", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ReadMetrics(opts...)
+		}
+	})
+
+	b.Run("sequential", func(b *testing.B) {
+		ctx := context.Background()
+		c := NewSystemCollector(opts...)
+		for i := 0; i < b.N; i++ {
+			m := Metrics{
+				MacAddrs:   make(map[string]string),
+				IPAddrs:    make(map[string][]string),
+				IPv4Addrs:  make(map[string][]string),
+				IPv6Addrs:  make(map[string][]string),
+				MTUs:       make(map[string]int),
+				LinkSpeeds: make(map[string]int),
+			}
+			for _, subsystem := range Subsystems() {
+				c.Collect(ctx, subsystem, &m)
+			}
+		}
+	})
+}
@@ -0,0 +1,80 @@
+//go:build linux
+
+package gonet
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+const cgroupPath = "/sys/fs/cgroup"
+
+// Files whose presence marks a container, as created by docker and podman.
+var containerEnvFiles = []string{"/.dockerenv", "/run/.containerenv"}
+
+// Names of /proc/1/cgroup entries of processes in a container.
+var containerCgroups = []string{"docker", "kubepods", "containerd", "libpod", "lxc"}
+
+// unlimitedCgroupMemory is the memory limit of cgroup v1 above which
+// memory is not limited, as unlimited cgroups report a page-rounded maximum.
+const unlimitedCgroupMemory = 1 << 62
+
+// getContainerLimits detects whether the process runs in a container and
+// reads the memory limit in bytes and the cpu quota in cpus of its cgroup,
+// v2 or else v1. The limits are 0 if unlimited.
+func getContainerLimits() (inContainer bool, memoryLimit uint64, cpus float64) {
+	inContainer = isContainer()
+
+	// cgroup v2: memory.max is "max" or bytes, cpu.max is "$quota $period"
+	if memoryMax := readSysString(cgroupPath, "memory.max"); memoryMax != "" {
+		memoryLimit, _ = strconv.ParseUint(memoryMax, 10, 64)
+		if fields := strings.Fields(readSysString(cgroupPath, "cpu.max")); len(fields) == 2 {
+			cpus = cpuQuota(fields[0], fields[1])
+		}
+		return inContainer, memoryLimit, cpus
+	}
+
+	// cgroup v1: quota is -1 if unlimited
+	if limit := readSysUint(cgroupPath+"/memory", "memory.limit_in_bytes"); limit < unlimitedCgroupMemory {
+		memoryLimit = limit
+	}
+	cpus = cpuQuota(readSysString(cgroupPath+"/cpu", "cpu.cfs_quota_us"), readSysString(cgroupPath+"/cpu", "cpu.cfs_period_us"))
+	return inContainer, memoryLimit, cpus
+}
+
+// cpuQuota returns the number of cpus a cgroup quota in a period allows,
+// or 0 if it is unlimited.
+func cpuQuota(quota, period string) float64 {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0
+	}
+
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0
+	}
+	return q / p
+}
+
+// isContainer reports whether the process runs in a container.
+func isContainer() bool {
+	for _, name := range containerEnvFiles {
+		if _, err := os.Stat(name); err == nil {
+			return true
+		}
+	}
+
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return true
+	}
+
+	cgroups := readSysString("/proc/1", "cgroup")
+	for _, name := range containerCgroups {
+		if strings.Contains(cgroups, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package gonet
+
+// getContainerLimits is only implemented on linux.
+func getContainerLimits() (inContainer bool, memoryLimit uint64, cpus float64) {
+	return false, 0, 0
+}
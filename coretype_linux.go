@@ -0,0 +1,95 @@
+//go:build linux
+
+package gonet
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Hybrid intel cpus list their performance and efficiency cores
+// in the cpus file of these devices.
+var hybridCoreTypes = map[string]string{
+	"/sys/devices/cpu_core": CoreTypePerformance,
+	"/sys/devices/cpu_atom": CoreTypeEfficiency,
+}
+
+const cpuSysPath = "/sys/devices/system/cpu"
+
+// getCoreTypes returns the type of each logical cpu by number, from the
+// hybrid cpu devices or else the capacity of each cpu reported by arm
+// systems. It is empty if all cpus are of the same type.
+func getCoreTypes() map[int]string {
+	types := make(map[int]string)
+	for device, coreType := range hybridCoreTypes {
+		for _, cpu := range parseCPUList(readSysString(device, "cpus")) {
+			types[cpu] = coreType
+		}
+	}
+	if len(types) > 0 {
+		return types
+	}
+
+	// the cores of arm big.LITTLE clusters have a lower capacity
+	// than the most capable ones
+	capacities := make(map[int]uint64)
+	var maxCapacity uint64
+	dirs, _ := filepath.Glob(filepath.Join(cpuSysPath, "cpu[0-9]*"))
+	for _, dir := range dirs {
+		cpu, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "cpu"))
+		if err != nil {
+			continue
+		}
+
+		capacity := readSysUint(dir, "cpu_capacity")
+		if capacity == 0 {
+			continue
+		}
+
+		capacities[cpu] = capacity
+		if capacity > maxCapacity {
+			maxCapacity = capacity
+		}
+	}
+
+	for cpu, capacity := range capacities {
+		if capacity != maxCapacity {
+			types[cpu] = CoreTypeEfficiency
+		}
+	}
+	if len(types) == 0 {
+		return nil
+	}
+
+	for cpu := range capacities {
+		if types[cpu] == "" {
+			types[cpu] = CoreTypePerformance
+		}
+	}
+	return types
+}
+
+// parseCPUList parses a list of cpu numbers and ranges, e.g. 0-7,16.
+func parseCPUList(list string) []int {
+	var cpus []int
+	for _, part := range strings.Split(list, ",") {
+		first, last, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			continue
+		}
+
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(last); err != nil {
+				continue
+			}
+		}
+
+		for cpu := start; cpu <= end; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package gonet
+
+// getCoreTypes is only implemented on linux.
+func getCoreTypes() map[int]string {
+	return nil
+}
@@ -0,0 +1,32 @@
+//go:build linux
+
+package gonet
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// getCPUFrequencies returns the current and maximum frequency in MHz of
+// each logical cpu by number, as reported by cpufreq. Cpus without
+// frequency scaling, e.g. in most virtual machines, are left out.
+func getCPUFrequencies() (current, max map[int]float64) {
+	current, max = make(map[int]float64), make(map[int]float64)
+	dirs, _ := filepath.Glob(filepath.Join(cpuSysPath, "cpu[0-9]*", "cpufreq"))
+	for _, dir := range dirs {
+		cpu, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(filepath.Dir(dir)), "cpu"))
+		if err != nil {
+			continue
+		}
+
+		// frequencies are in kHz
+		if khz := readSysUint(dir, "scaling_cur_freq"); khz > 0 {
+			current[cpu] = float64(khz) / 1000
+		}
+		if khz := readSysUint(dir, "cpuinfo_max_freq"); khz > 0 {
+			max[cpu] = float64(khz) / 1000
+		}
+	}
+	return current, max
+}
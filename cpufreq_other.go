@@ -0,0 +1,8 @@
+//go:build !linux
+
+package gonet
+
+// getCPUFrequencies is only implemented on linux.
+func getCPUFrequencies() (current, max map[int]float64) {
+	return nil, nil
+}
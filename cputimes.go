@@ -0,0 +1,74 @@
+package gonet
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// CPUTimes breaks down the cpu time of all cores, in percent of the
+// time elapsed between two samples. Steal is the time a VM waited for
+// the hypervisor to run it and IOWait the time idle cores waited for I/O.
+// IOWait and Steal are only reported on linux.
+type CPUTimes struct {
+	User   float64 `json:"user" yaml:"user"`
+	System float64 `json:"system" yaml:"system"`
+	Idle   float64 `json:"idle" yaml:"idle"`
+	IOWait float64 `json:"iowait" yaml:"iowait"`
+	Steal  float64 `json:"steal" yaml:"steal"`
+}
+
+// getCPUTimes samples the cpu times twice, interval apart.
+func getCPUTimes(ctx context.Context, interval time.Duration) (*CPUTimes, error) {
+	sample := func() (cpu.TimesStat, error) {
+		times, err := withContext(ctx, func(ctx context.Context) ([]cpu.TimesStat, error) {
+			return cpu.TimesWithContext(ctx, false)
+		})
+		if err != nil {
+			return cpu.TimesStat{}, err
+		}
+
+		if len(times) == 0 {
+			return cpu.TimesStat{}, errors.New("no cpu times returned")
+		}
+		return times[0], nil
+	}
+
+	first, err := sample()
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(interval):
+	}
+
+	second, err := sample()
+	if err != nil {
+		return nil, err
+	}
+
+	total := second.Total() - first.Total()
+	if total <= 0 {
+		return nil, errors.New("cpu times did not advance")
+	}
+
+	share := func(a, b float64) float64 {
+		if b < a {
+			return 0
+		}
+		return (b - a) / total * 100
+	}
+
+	return &CPUTimes{
+		User:   share(first.User, second.User),
+		System: share(first.System, second.System),
+		Idle:   share(first.Idle, second.Idle),
+		IOWait: share(first.Iowait, second.Iowait),
+		Steal:  share(first.Steal, second.Steal),
+	}, nil
+}
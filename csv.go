@@ -0,0 +1,60 @@
+package gonet
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// WriteMetricsCSV writes the key metrics to the given writer as CSV,
+// a header row followed by a single row of values. Byte counts are
+// written as raw numbers. Values of subsystems that could not be read
+// are left empty.
+//
+// The metrics are written even if some subsystems could not be read,
+// in which case the collection error from ReadMetrics is returned.
+func WriteMetricsCSV(w io.Writer, opts ...Option) error {
+	return WriteMetricsAs(w, FormatCSV, opts...)
+}
+
+func writeCSV(w io.Writer, m Metrics, failed map[string]bool) error {
+	num := func(subsystem string, v uint64) string {
+		if failed[subsystem] {
+			return ""
+		}
+		return strconv.FormatUint(v, 10)
+	}
+
+	text := func(subsystem string, v string) string {
+		if failed[subsystem] {
+			return ""
+		}
+		return v
+	}
+
+	float := func(subsystem string, v float64) string {
+		if failed[subsystem] {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{
+		"collected_at", "cpu_percent", "go_num_cpu",
+		"total_memory", "used_memory", "free_memory", "memory_used_percent",
+		"disk_path", "disk_size", "disk_usage", "disk_free", "disk_used_percent",
+		"running_processes", "hostname", "platform",
+	})
+	cw.Write([]string{
+		m.CollectedAt.Format(time.RFC3339), float(SubsystemCPUPercent, m.CPUPercent), strconv.Itoa(m.GoNumCPU),
+		num(SubsystemMemory, m.TotalMemory), num(SubsystemMemory, m.UsedMemory), num(SubsystemMemory, m.FreeMemory),
+		float(SubsystemMemory, m.MemoryUsedPercent),
+		m.DiskPath, num(SubsystemDisk, m.DiskSize), num(SubsystemDisk, m.DiskUsage), num(SubsystemDisk, m.DiskFree),
+		float(SubsystemDisk, m.DiskUsedPercent),
+		num(SubsystemHost, m.RunningProcesses), text(SubsystemHost, m.Hostname), text(SubsystemHost, m.Platform),
+	})
+	cw.Flush()
+	return cw.Error()
+}
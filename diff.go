@@ -0,0 +1,114 @@
+package gonet
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jedib0t/go-pretty/table"
+)
+
+// MetricsDelta holds the change between two metrics snapshots.
+// Byte counts are signed, negative when the value went down.
+type MetricsDelta struct {
+	CPUPercent float64 `json:"cpu_percent" yaml:"cpu_percent"`
+	UsedMemory int64   `json:"used_memory" yaml:"used_memory"`
+	DiskUsage  int64   `json:"disk_usage" yaml:"disk_usage"`
+
+	// Change in used bytes of each filesystem present in both snapshots
+	Disks map[string]int64 `json:"disks" yaml:"disks"`
+
+	// Change in I/O counters of each interface present in both snapshots
+	NetIO map[string]NetIODelta `json:"net_io" yaml:"net_io"`
+}
+
+// NetIODelta holds the change in the I/O counters of a network interface.
+type NetIODelta struct {
+	BytesSent int64 `json:"bytes_sent" yaml:"bytes_sent"`
+	BytesRecv int64 `json:"bytes_recv" yaml:"bytes_recv"`
+}
+
+// DiffMetrics returns the change from before to after.
+func DiffMetrics(before, after Metrics) MetricsDelta {
+	d := MetricsDelta{
+		CPUPercent: after.CPUPercent - before.CPUPercent,
+		UsedMemory: int64(after.UsedMemory) - int64(before.UsedMemory),
+		DiskUsage:  int64(after.DiskUsage) - int64(before.DiskUsage),
+		Disks:      make(map[string]int64),
+		NetIO:      make(map[string]NetIODelta),
+	}
+
+	used := make(map[string]uint64, len(before.Disks))
+	for _, disk := range before.Disks {
+		used[disk.Mountpoint] = disk.Used
+	}
+
+	for _, disk := range after.Disks {
+		if prev, ok := used[disk.Mountpoint]; ok {
+			d.Disks[disk.Mountpoint] = int64(disk.Used) - int64(prev)
+		}
+	}
+
+	for iface, c := range after.NetIO {
+		if prev, ok := before.NetIO[iface]; ok {
+			d.NetIO[iface] = NetIODelta{
+				BytesSent: int64(c.BytesSent) - int64(prev.BytesSent),
+				BytesRecv: int64(c.BytesRecv) - int64(prev.BytesRecv),
+			}
+		}
+	}
+	return d
+}
+
+// WriteDelta writes the change between two snapshots
+// to the given writer as tables, with +/- signs.
+// If writer is nil, it will write to stdout
+// WriteDelta returns the first write error.
+func WriteDelta(writer io.Writer, d MetricsDelta, opts ...Option) error {
+	if writer == nil {
+		writer = os.Stdout
+	}
+
+	o := newOptions(opts)
+	style := o.tableStyle(writer, table.StyleColoredBright)
+	width := o.tableWidth(writer)
+
+	t := newTable("Change", "CPU Usage", "Used Memory", "Disk Usage")
+	t.AppendRow(table.Row{
+		fmt.Sprintf("%+.2f%%", d.CPUPercent), signedBytes(d.UsedMemory, o), signedBytes(d.DiskUsage, o),
+	})
+
+	td := newTable("Disk Usage Change", "Mountpoint", "Used")
+	for _, mountpoint := range sortedKeys(d.Disks) {
+		td.AppendRow(table.Row{mountpoint, signedBytes(d.Disks[mountpoint], o)})
+	}
+
+	tn := newTable("Network I/O Change", "Interface", "Bytes Sent", "Bytes Recv")
+	for _, iface := range sortedKeys(d.NetIO) {
+		c := d.NetIO[iface]
+		tn.AppendRow(table.Row{iface, signedBytes(c.BytesSent, o), signedBytes(c.BytesRecv, o)})
+	}
+
+	ew := &errWriter{w: writer}
+	fmt.Fprintln(ew)
+	for _, t := range []*titledTable{t, td, tn} {
+		t.localize(o.locale)
+		t.SetStyle(style)
+		t.fit(width)
+		fmt.Fprintln(ew, t.Render())
+		fmt.Fprintln(ew)
+	}
+	return ew.err
+}
+
+// signedBytes formats a change in bytes with its sign, e.g. +1.50 MiB.
+func signedBytes(delta int64, o *options) string {
+	switch {
+	case delta > 0:
+		return "+" + o.humanReadable(uint64(delta))
+	case delta < 0:
+		return "-" + o.humanReadable(uint64(-delta))
+	default:
+		return o.humanReadable(0)
+	}
+}
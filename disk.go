@@ -0,0 +1,190 @@
+package gonet
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+// DiskUsage holds the usage of a single mounted filesystem.
+type DiskUsage struct {
+	Mountpoint  string  `json:"mountpoint" yaml:"mountpoint"`
+	Fstype      string  `json:"fstype" yaml:"fstype"`
+	Total       uint64  `json:"total" yaml:"total"`
+	Free        uint64  `json:"free" yaml:"free"`
+	Used        uint64  `json:"used" yaml:"used"`
+	UsedPercent float64 `json:"used_percent" yaml:"used_percent"`
+
+	// Inodes of the filesystem, zero on filesystems without a fixed
+	// number of them, e.g. btrfs, and on windows
+	InodesTotal       uint64  `json:"inodes_total" yaml:"inodes_total"`
+	InodesFree        uint64  `json:"inodes_free" yaml:"inodes_free"`
+	InodesUsed        uint64  `json:"inodes_used" yaml:"inodes_used"`
+	InodesUsedPercent float64 `json:"inodes_used_percent" yaml:"inodes_used_percent"`
+}
+
+// pseudoFilesystems lists filesystem types that do not
+// correspond to disk storage and are skipped by default.
+var pseudoFilesystems = map[string]bool{
+	"proc":        true,
+	"sysfs":       true,
+	"devfs":       true,
+	"devtmpfs":    true,
+	"devpts":      true,
+	"tmpfs":       true,
+	"cgroup":      true,
+	"cgroup2":     true,
+	"overlay":     true,
+	"squashfs":    true,
+	"autofs":      true,
+	"mqueue":      true,
+	"debugfs":     true,
+	"tracefs":     true,
+	"securityfs":  true,
+	"pstore":      true,
+	"bpf":         true,
+	"configfs":    true,
+	"fusectl":     true,
+	"hugetlbfs":   true,
+	"binfmt_misc": true,
+	"nsfs":        true,
+}
+
+// defaultDiskPath returns the path of the root filesystem,
+// the system drive on windows.
+func defaultDiskPath() string {
+	if runtime.GOOS == "windows" {
+		drive := os.Getenv("SystemDrive")
+		if drive == "" {
+			drive = "C:"
+		}
+		return drive + `\`
+	}
+	return "/"
+}
+
+// getDiskUsage returns disk usage information for the filesystem at path.
+func getDiskUsage(ctx context.Context, path string) (*disk.UsageStat, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	return disk.UsageWithContext(ctx, path)
+}
+
+// getPartitionsUsage returns the usage of every mounted filesystem.
+// Pseudo filesystems are skipped unless includePseudo is true.
+// Mountpoints whose usage cannot be read before ctx is done are skipped.
+func getPartitionsUsage(ctx context.Context, includePseudo bool) ([]DiskUsage, error) {
+	partitions, err := withContext(ctx, func(ctx context.Context) ([]disk.PartitionStat, error) {
+		return disk.PartitionsWithContext(ctx, includePseudo)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var disks []DiskUsage
+	seen := make(map[string]bool)
+	for _, p := range partitions {
+		if seen[p.Mountpoint] || (!includePseudo && pseudoFilesystems[p.Fstype]) {
+			continue
+		}
+		seen[p.Mountpoint] = true
+
+		// p is reused by the next iteration while an abandoned read may still run
+		mountpoint := p.Mountpoint
+		du, err := withContext(ctx, func(ctx context.Context) (*disk.UsageStat, error) {
+			return disk.UsageWithContext(ctx, mountpoint)
+		})
+		if err != nil {
+			continue
+		}
+
+		d := DiskUsage{
+			Mountpoint: p.Mountpoint,
+			Fstype:     p.Fstype,
+			Total:      du.Total,
+			Used:       du.Used,
+			Free:       du.Total - du.Used,
+
+			InodesTotal: du.InodesTotal,
+			InodesFree:  du.InodesFree,
+			InodesUsed:  du.InodesUsed,
+		}
+
+		d.UsedPercent = percent(d.Used, d.Total)
+		d.InodesUsedPercent = percent(d.InodesUsed, d.InodesTotal)
+		disks = append(disks, d)
+	}
+	return disks, nil
+}
+
+// DiskIOCounters holds the cumulative I/O counters of a block device.
+// The rates are only set when sampled with WithDiskRateInterval.
+type DiskIOCounters struct {
+	ReadBytes  uint64 `json:"read_bytes" yaml:"read_bytes"`
+	WriteBytes uint64 `json:"write_bytes" yaml:"write_bytes"`
+	ReadCount  uint64 `json:"read_count" yaml:"read_count"`
+	WriteCount uint64 `json:"write_count" yaml:"write_count"`
+
+	// Bytes and operations per second over the sampling interval
+	ReadBytesRate  float64 `json:"read_bytes_rate" yaml:"read_bytes_rate"`
+	WriteBytesRate float64 `json:"write_bytes_rate" yaml:"write_bytes_rate"`
+	ReadCountRate  float64 `json:"read_count_rate" yaml:"read_count_rate"`
+	WriteCountRate float64 `json:"write_count_rate" yaml:"write_count_rate"`
+}
+
+// getDiskIO returns the I/O counters of every block device keyed by name.
+// If interval is non-zero, the counters are sampled twice, interval apart,
+// to compute the rates.
+func getDiskIO(ctx context.Context, interval time.Duration) (map[string]DiskIOCounters, error) {
+	first, err := withContext(ctx, func(ctx context.Context) (map[string]disk.IOCountersStat, error) {
+		return disk.IOCountersWithContext(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[string]DiskIOCounters, len(first))
+	for name, c := range first {
+		counters[name] = DiskIOCounters{
+			ReadBytes:  c.ReadBytes,
+			WriteBytes: c.WriteBytes,
+			ReadCount:  c.ReadCount,
+			WriteCount: c.WriteCount,
+		}
+	}
+
+	if interval <= 0 {
+		return counters, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(interval):
+	}
+
+	second, err := withContext(ctx, func(ctx context.Context) (map[string]disk.IOCountersStat, error) {
+		return disk.IOCountersWithContext(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name, c := range second {
+		prev, ok := counters[name]
+		if !ok {
+			continue
+		}
+
+		prev.ReadBytesRate = perSecond(prev.ReadBytes, c.ReadBytes, interval)
+		prev.WriteBytesRate = perSecond(prev.WriteBytes, c.WriteBytes, interval)
+		prev.ReadCountRate = perSecond(prev.ReadCount, c.ReadCount, interval)
+		prev.WriteCountRate = perSecond(prev.WriteCount, c.WriteCount, interval)
+		counters[name] = prev
+	}
+	return counters, nil
+}
@@ -0,0 +1,23 @@
+package gonet
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestGetPartitionsUsageDeadline abandons usage reads part way through the
+// partitions, which go test -race reports if they share loop variables.
+func TestGetPartitionsUsageDeadline(t *testing.T) {
+	for timeout := 10 * time.Microsecond; timeout < 10*time.Millisecond; timeout *= 2 {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		disks, _ := getPartitionsUsage(ctx, true)
+		cancel()
+
+		for _, d := range disks {
+			if d.Mountpoint == "" {
+				t.Errorf("timeout %s: partition without mountpoint", timeout)
+			}
+		}
+	}
+}
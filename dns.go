@@ -0,0 +1,35 @@
+//go:build !windows
+
+package gonet
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+const resolvConfPath = "/etc/resolv.conf"
+
+// getDNSServers returns the nameservers configured in /etc/resolv.conf.
+// Systems without it have none and give no error.
+func getDNSServers() ([]string, error) {
+	f, err := os.Open(resolvConfPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var servers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			servers = append(servers, fields[1])
+		}
+	}
+	return servers, scanner.Err()
+}
@@ -0,0 +1,25 @@
+package gonet
+
+// getDNSServers returns the DNS servers of the adapters that are up,
+// without duplicates.
+func getDNSServers() ([]string, error) {
+	adapters, err := getAdapterAddresses()
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []string
+	seen := make(map[string]bool)
+	for _, a := range adapters {
+		for d := a.FirstDnsServerAddress; d != nil; d = d.Next {
+			ip := d.Address.IP()
+			if ip == nil || seen[ip.String()] {
+				continue
+			}
+
+			seen[ip.String()] = true
+			servers = append(servers, ip.String())
+		}
+	}
+	return servers, nil
+}
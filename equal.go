@@ -0,0 +1,65 @@
+package gonet
+
+import (
+	"math"
+	"reflect"
+)
+
+// Tolerances within which Equal considers usages unchanged, in percentage points.
+const (
+	cpuTolerance   = 5.0
+	usageTolerance = 1.0
+)
+
+// Equal reports whether m and other describe the same state of the system,
+// e.g. to skip re-rendering or logging a snapshot when nothing changed.
+//
+// It compares the host, the totals of memory and disks, the network
+// interfaces and addresses, the gateway, DNS servers and batteries.
+// Cpu usage may differ by 5 percentage points and memory, disk and battery
+// usage by 1. Counters and values that change on every read are ignored:
+// CollectedAt, uptime, load, I/O counters, processes, temperatures and the
+// Go runtime.
+func (m Metrics) Equal(other Metrics) bool {
+	within := func(a, b, tolerance float64) bool {
+		return math.Abs(a-b) <= tolerance
+	}
+
+	if m.Hostname != other.Hostname || m.Platform != other.Platform ||
+		m.PlatformVersion != other.PlatformVersion || !m.BootTime.Equal(other.BootTime) {
+		return false
+	}
+
+	if !within(m.CPUPercent, other.CPUPercent, cpuTolerance) ||
+		m.TotalMemory != other.TotalMemory ||
+		!within(m.MemoryUsedPercent, other.MemoryUsedPercent, usageTolerance) ||
+		m.DiskPath != other.DiskPath || m.DiskSize != other.DiskSize ||
+		!within(m.DiskUsedPercent, other.DiskUsedPercent, usageTolerance) {
+		return false
+	}
+
+	if len(m.Disks) != len(other.Disks) {
+		return false
+	}
+	for i, d := range m.Disks {
+		o := other.Disks[i]
+		if d.Mountpoint != o.Mountpoint || d.Total != o.Total || !within(d.UsedPercent, o.UsedPercent, usageTolerance) {
+			return false
+		}
+	}
+
+	if len(m.Batteries) != len(other.Batteries) {
+		return false
+	}
+	for i, b := range m.Batteries {
+		o := other.Batteries[i]
+		if b.Name != o.Name || b.State != o.State || !within(b.Percent, o.Percent, usageTolerance) {
+			return false
+		}
+	}
+
+	return reflect.DeepEqual(m.MacAddrs, other.MacAddrs) &&
+		reflect.DeepEqual(m.IPAddrs, other.IPAddrs) &&
+		m.DefaultGateway == other.DefaultGateway &&
+		reflect.DeepEqual(m.DNSServers, other.DNSServers)
+}
@@ -0,0 +1,66 @@
+package gonet
+
+import (
+	"encoding/json"
+	"errors"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SubsystemErrors holds the error of every subsystem that could not be
+// read, keyed by subsystem, e.g. SubsystemDisk. The errors are encoded as
+// their messages in JSON and YAML, and decoded back as errors.New.
+type SubsystemErrors map[string]error
+
+func (e SubsystemErrors) messages() map[string]string {
+	messages := make(map[string]string, len(e))
+	for subsystem, err := range e {
+		messages[subsystem] = err.Error()
+	}
+	return messages
+}
+
+func (e SubsystemErrors) fromMessages(messages map[string]string) {
+	for subsystem, message := range messages {
+		e[subsystem] = errors.New(message)
+	}
+}
+
+func (e SubsystemErrors) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.messages())
+}
+
+func (e *SubsystemErrors) UnmarshalJSON(data []byte) error {
+	var messages map[string]string
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return err
+	}
+
+	*e = make(SubsystemErrors, len(messages))
+	e.fromMessages(messages)
+	return nil
+}
+
+func (e SubsystemErrors) MarshalYAML() (interface{}, error) {
+	return e.messages(), nil
+}
+
+func (e *SubsystemErrors) UnmarshalYAML(value *yaml.Node) error {
+	var messages map[string]string
+	if err := value.Decode(&messages); err != nil {
+		return err
+	}
+
+	*e = make(SubsystemErrors, len(messages))
+	e.fromMessages(messages)
+	return nil
+}
+
+// failed returns the set of subsystems in e.
+func (e SubsystemErrors) failed() map[string]bool {
+	failed := make(map[string]bool, len(e))
+	for subsystem := range e {
+		failed[subsystem] = true
+	}
+	return failed
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+
+package gonet
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+)
+
+// getFileDescriptors returns the number of file descriptors open in this
+// process and its soft RLIMIT_NOFILE limit.
+func getFileDescriptors() (open, max uint64, err error) {
+	var limit syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil {
+		return 0, 0, err
+	}
+
+	dir := "/dev/fd"
+	if runtime.GOOS == "linux" {
+		dir = "/proc/self/fd"
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, uint64(limit.Cur), err
+	}
+
+	// the directory being read holds a descriptor of its own
+	open = uint64(len(entries))
+	if open > 0 {
+		open--
+	}
+	return open, uint64(limit.Cur), nil
+}
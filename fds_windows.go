@@ -0,0 +1,7 @@
+package gonet
+
+// getFileDescriptors is not implemented on windows,
+// where processes have handles rather than file descriptors.
+func getFileDescriptors() (open, max uint64, err error) {
+	return 0, 0, nil
+}
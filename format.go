@@ -0,0 +1,151 @@
+package gonet
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format is an output format of the metrics.
+type Format int
+
+const (
+	FormatTable      Format = iota // tables, as written by WriteMetrics
+	FormatJSON                     // indented JSON, as written by WriteMetricsJSON
+	FormatCSV                      // CSV, as written by WriteMetricsCSV
+	FormatYAML                     // YAML, as written by WriteMetricsYAML
+	FormatMarkdown                 // Markdown tables, as written by WriteMetricsMarkdown
+	FormatHTML                     // an HTML page, as written by WriteMetricsHTML
+	FormatPrometheus               // prometheus text format, as written by WritePrometheus
+	FormatKV                       // key=value lines, as written by WriteMetricsKV
+	FormatInflux                   // InfluxDB line protocol, as written by WriteInfluxLineProtocol
+)
+
+// formatNames are the names of the formats, as accepted by ParseFormat.
+var formatNames = map[Format]string{
+	FormatTable:      "table",
+	FormatJSON:       "json",
+	FormatCSV:        "csv",
+	FormatYAML:       "yaml",
+	FormatMarkdown:   "markdown",
+	FormatHTML:       "html",
+	FormatPrometheus: "prometheus",
+	FormatKV:         "kv",
+	FormatInflux:     "influx",
+}
+
+func (f Format) String() string {
+	if name, ok := formatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// ParseFormat returns the format with the given name, as returned by
+// Format.String, e.g. for command line flags.
+func ParseFormat(name string) (Format, error) {
+	for f, n := range formatNames {
+		if strings.EqualFold(name, n) {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("gonet: unknown format %q", name)
+}
+
+// WriteMetricsAs writes metrics to the given writer in the given format.
+//
+// The metrics are written even if some subsystems could not be read,
+// in which case the collection error from ReadMetrics is returned.
+func WriteMetricsAs(w io.Writer, format Format, opts ...Option) error {
+	opts = format.readOptions(opts)
+	metrics, err := ReadMetrics(opts...)
+	if werr := format.write(w, metrics, failedSubsystems(err), newOptions(opts)); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// readOptions returns opts for reading the metrics written in format f.
+// Formats that don't write the top processes don't read them by default.
+func (f Format) readOptions(opts []Option) []Option {
+	switch f {
+	case FormatCSV, FormatPrometheus, FormatInflux:
+		return append([]Option{WithTopProcesses(0)}, opts...)
+	default:
+		return opts
+	}
+}
+
+// write writes m to w in format f.
+func (f Format) write(w io.Writer, m Metrics, failed map[string]bool, o *options) error {
+	switch f {
+	case FormatTable:
+		return renderMetrics(w, m, failed, o)
+	case FormatJSON:
+		return writeJSON(w, m)
+	case FormatCSV:
+		return writeCSV(w, m, failed)
+	case FormatYAML:
+		return writeYAML(w, m)
+	case FormatMarkdown:
+		return writeMarkdown(w, m, failed, o)
+	case FormatHTML:
+		return writeHTML(w, m, failed, o)
+	case FormatPrometheus:
+		return writePrometheus(w, m, failed)
+	case FormatKV:
+		return writeKV(w, m)
+	case FormatInflux:
+		return writeInflux(w, m, failed, nil)
+	default:
+		return fmt.Errorf("gonet: unsupported format %s", f)
+	}
+}
+
+// WriteMetricsToFile writes metrics to the file at path in the given format.
+// The file is replaced atomically: the metrics are written to a temporary
+// file in the same directory that is renamed to path, so readers never see
+// a partial file.
+//
+// The metrics are written even if some subsystems could not be read,
+// in which case the collection error from ReadMetrics is returned.
+func WriteMetricsToFile(path string, format Format, opts ...Option) error {
+	opts = format.readOptions(opts)
+	metrics, err := ReadMetrics(opts...)
+	if werr := writeFileAtomic(path, func(w io.Writer) error {
+		return format.write(w, metrics, failedSubsystems(err), newOptions(opts))
+	}); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// writeFileAtomic replaces the file at path with the output of write.
+func writeFileAtomic(path string, write func(w io.Writer) error) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+
+	if err = write(f); err != nil {
+		return err
+	}
+	if err = f.Chmod(0o644); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,45 @@
+package gonet
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	for f, name := range formatNames {
+		got, err := ParseFormat(strings.ToUpper(name))
+		if err != nil || got != f {
+			t.Errorf("ParseFormat(%q) = %v, %v, want %v", strings.ToUpper(name), got, err, f)
+		}
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error(`ParseFormat("xml") returned no error`)
+	}
+}
+
+func TestFormatWrite(t *testing.T) {
+	for f := range formatNames {
+		var buf bytes.Buffer
+		if err := f.write(&buf, testMetrics(), nil, newOptions([]Option{WithNoColor()})); err != nil {
+			t.Errorf("%s: %v", f, err)
+		}
+
+		if buf.Len() == 0 {
+			t.Errorf("%s: wrote nothing", f)
+		}
+	}
+}
+
+func TestFormatInflux(t *testing.T) {
+	var buf bytes.Buffer
+	if err := FormatInflux.write(&buf, testMetrics(), nil, newOptions(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "mem,host=web01 total=17179869184i,used=10737418240i,free=4294967296i,cached=2147483648i,used_percent=62.5 1709296200000000000\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("influx output has no line %q:\n%s", want, buf.String())
+	}
+}
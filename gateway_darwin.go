@@ -0,0 +1,30 @@
+//go:build darwin
+
+package gonet
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+)
+
+// getDefaultGateway returns the gateway of the default route reported by
+// route(8), or "" if there is no default route.
+func getDefaultGateway(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, "route", "-n", "get", "default").Output()
+	if err != nil {
+		// route exits with an error when there is no default route
+		return "", nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if ok && key == "gateway" {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", scanner.Err()
+}
@@ -0,0 +1,45 @@
+//go:build linux
+
+package gonet
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const routeTablePath = "/proc/net/route"
+
+// getDefaultGateway returns the gateway of the default IPv4 route in
+// /proc/net/route, or "" if there is no default route.
+func getDefaultGateway(ctx context.Context) (string, error) {
+	f, err := os.Open(routeTablePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// Iface Destination Gateway Flags ... with addresses in little-endian hex
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+
+		gateway, err := strconv.ParseUint(fields[2], 16, 32)
+		if err != nil || gateway == 0 {
+			continue
+		}
+
+		var ip [4]byte
+		binary.LittleEndian.PutUint32(ip[:], uint32(gateway))
+		return netip.AddrFrom4(ip).String(), nil
+	}
+	return "", scanner.Err()
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin && !windows
+
+package gonet
+
+import "context"
+
+// getDefaultGateway is only implemented on linux, darwin and windows.
+func getDefaultGateway(ctx context.Context) (string, error) {
+	return "", nil
+}
@@ -0,0 +1,31 @@
+package gonet
+
+import (
+	"context"
+	"math"
+)
+
+// getDefaultGateway returns the first IPv4 gateway of the adapter with the
+// lowest IPv4 metric, the one windows routes through by default, or "" if
+// no adapter has one.
+func getDefaultGateway(ctx context.Context) (string, error) {
+	adapters, err := getAdapterAddresses()
+	if err != nil {
+		return "", err
+	}
+
+	gateway, metric := "", uint32(math.MaxUint32)
+	for _, a := range adapters {
+		if a.Ipv4Metric >= metric {
+			continue
+		}
+
+		for g := a.FirstGatewayAddress; g != nil; g = g.Next {
+			if ip := g.Address.IP(); ip != nil && ip.To4() != nil {
+				gateway, metric = ip.String(), a.Ipv4Metric
+				break
+			}
+		}
+	}
+	return gateway, nil
+}
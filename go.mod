@@ -1,10 +1,14 @@
 module github.com/abiiranathan/gonet
 
-go 1.18
+go 1.20
 
 require (
 	github.com/jedib0t/go-pretty v4.3.0+incompatible
 	github.com/shirou/gopsutil/v3 v3.22.3
+	golang.org/x/crypto v0.6.0
+	golang.org/x/sys v0.5.0
+	golang.org/x/term v0.5.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -23,5 +27,4 @@ require (
 	github.com/tklauser/numcpus v0.4.0 // indirect
 	github.com/yusufpapurcu/wmi v1.2.2 // indirect
 	go.mongodb.org/mongo-driver v1.7.5 // indirect
-	golang.org/x/sys v0.0.0-20220128215802-99c3d69c2c27 // indirect
 )
@@ -3,251 +3,656 @@
 package gonet
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"runtime"
+	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
-	"syscall"
+	"sync"
+	"time"
 
 	"github.com/jedib0t/go-pretty/table"
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/host"
-	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/net"
 )
 
-type sysMetrics struct {
+// SchemaVersion is the version of the JSON and YAML encoding of Metrics,
+// following semantic versioning: the major version is bumped whenever a
+// field is removed, renamed or changes type, the minor version when fields
+// are added.
+const SchemaVersion = "1.8.0"
+
+// Metrics holds a snapshot of the system metrics read by ReadMetrics.
+type Metrics struct {
+	// SchemaVersion of the metrics, set by ReadMetrics
+	SchemaVersion string `json:"schema_version" yaml:"schema_version"`
+
+	// When the collection of the metrics started
+	CollectedAt time.Time `json:"collected_at" yaml:"collected_at"`
+
+	// Errors of the subsystems that could not be read, nil if all were.
+	// They are also returned by ReadMetrics as *CollectError values.
+	Errors SubsystemErrors `json:"errors,omitempty" yaml:"errors,omitempty"`
+
 	// Disk usage
-	DiskSize  uint64
-	DiskFree  uint64
-	DiskUsage uint64
+	DiskPath  string `json:"disk_path" yaml:"disk_path"`
+	DiskSize  uint64 `json:"disk_size" yaml:"disk_size"`
+	DiskFree  uint64 `json:"disk_free" yaml:"disk_free"`
+	DiskUsage uint64 `json:"disk_usage" yaml:"disk_usage"`
+
+	// DiskUsage as a percentage of DiskSize
+	DiskUsedPercent float64 `json:"disk_used_percent" yaml:"disk_used_percent"`
+
+	// Usage of every mounted filesystem
+	Disks []DiskUsage `json:"disks" yaml:"disks"`
+
+	// I/O counters of each block device, only read with WithDiskIO
+	DiskIO map[string]DiskIOCounters `json:"disk_io" yaml:"disk_io"`
 
 	// System Memory
-	TotalMemory uint64
-	FreeMemory  uint64
-	UsedMemory  uint64
-	CacheMemory uint64
+	TotalMemory uint64 `json:"total_memory" yaml:"total_memory"`
+	FreeMemory  uint64 `json:"free_memory" yaml:"free_memory"`
+	UsedMemory  uint64 `json:"used_memory" yaml:"used_memory"`
+	CacheMemory uint64 `json:"cache_memory" yaml:"cache_memory"`
+
+	// UsedMemory as a percentage of TotalMemory
+	MemoryUsedPercent float64 `json:"memory_used_percent" yaml:"memory_used_percent"`
+
+	// Whether gonet runs in a container, and the memory limit in bytes and
+	// cpu quota in cpus of its cgroup, 0 if unlimited
+	InContainer       bool    `json:"in_container" yaml:"in_container"`
+	ContainerMemLimit uint64  `json:"container_mem_limit" yaml:"container_mem_limit"`
+	ContainerCPUQuota float64 `json:"container_cpu_quota" yaml:"container_cpu_quota"`
 
 	// CPU info
-	GoNumCPU   int
-	CPUInfo    []cpuinfo
-	CPUPercent float64
+	GoNumCPU   int       `json:"go_num_cpu" yaml:"go_num_cpu"`
+	CPUInfo    []CPUInfo `json:"cpu_info" yaml:"cpu_info"`
+	CPUPercent float64   `json:"cpu_percent" yaml:"cpu_percent"`
+
+	// Number of logical cpus (hardware threads) and physical cores
+	LogicalCores  int `json:"logical_cores" yaml:"logical_cores"`
+	PhysicalCores int `json:"physical_cores" yaml:"physical_cores"`
+
+	// Breakdown of the cpu time, nil unless WithCPUTimesInterval is set
+	CPUTimes *CPUTimes `json:"cpu_times,omitempty" yaml:"cpu_times,omitempty"`
+
+	// Usage of each logical core
+	PerCorePercent []float64 `json:"per_core_percent" yaml:"per_core_percent"`
 
 	// host, platform
-	Hostname         string
-	RunningProcesses uint64
-	Platform         string
-	PlatformVersion  string
+	Hostname         string  `json:"hostname" yaml:"hostname"`
+	RunningProcesses uint64  `json:"running_processes" yaml:"running_processes"`
+	Platform         string  `json:"platform" yaml:"platform"`
+	PlatformVersion  string  `json:"platform_version" yaml:"platform_version"`
+	LoadAvg          LoadAvg `json:"load_avg" yaml:"load_avg"`
+
+	// Virtualization of the host, e.g. "kvm" and "guest".
+	// Both are empty on bare metal or when it can't be detected.
+	VirtSystem string `json:"virt_system" yaml:"virt_system"`
+	VirtRole   string `json:"virt_role" yaml:"virt_role"`
+
+	// NVIDIA GPUs, only read with WithGPU
+	GPUs []GPU `json:"gpus" yaml:"gpus"`
+
+	// Vendor, product and firmware of the machine, where exposed
+	Hardware Hardware `json:"hardware" yaml:"hardware"`
+
+	// Uptime and time of the last boot
+	Uptime   time.Duration `json:"uptime" yaml:"uptime"`
+	BootTime time.Time     `json:"boot_time" yaml:"boot_time"`
+
+	// Go build environment and runtime
+	GOOS         string `json:"goos" yaml:"goos"`
+	GOARCH       string `json:"goarch" yaml:"goarch"`
+	GoVersion    string `json:"go_version" yaml:"go_version"`
+	NumGoroutine int    `json:"num_goroutine" yaml:"num_goroutine"`
+
+	// Version of gopsutil, which reads the disk, memory, cpu, host, network
+	// and process metrics, empty if gonet was built without module support
+	// or the metrics were read WithCollector
+	GopsutilVersion string `json:"gopsutil_version" yaml:"gopsutil_version"`
+
+	// Memory allocated by the Go runtime of this process
+	GoMemory GoMemStats `json:"go_memory" yaml:"go_memory"`
+
+	// File descriptors open in this process and its RLIMIT_NOFILE,
+	// 0 on windows
+	OpenFDs uint64 `json:"open_fds" yaml:"open_fds"`
+	MaxFDs  uint64 `json:"max_fds" yaml:"max_fds"`
 
 	// network identifiers
-	MacAddr string
-	IPAddrs map[string][]string
+	//
+	// Deprecated: MacAddr is the hardware address of the first interface
+	// that has one. Use MacAddrs instead.
+	MacAddr  string              `json:"mac_addr" yaml:"mac_addr"`
+	MacAddrs map[string]string   `json:"mac_addrs" yaml:"mac_addrs"`
+	IPAddrs  map[string][]string `json:"ip_addrs" yaml:"ip_addrs"`
+
+	// IPAddrs of each interface split by address family
+	IPv4Addrs map[string][]string `json:"ipv4_addrs" yaml:"ipv4_addrs"`
+	IPv6Addrs map[string][]string `json:"ipv6_addrs" yaml:"ipv6_addrs"`
+
+	// MTU and negotiated link speed in Mb/s of each interface,
+	// left out where unknown. Speeds are only read on linux.
+	MTUs       map[string]int `json:"mtus" yaml:"mtus"`
+	LinkSpeeds map[string]int `json:"link_speeds" yaml:"link_speeds"`
+
+	// Gateway of the default IPv4 route and the configured DNS servers,
+	// empty where the platform doesn't expose them
+	DefaultGateway string   `json:"default_gateway" yaml:"default_gateway"`
+	DNSServers     []string `json:"dns_servers" yaml:"dns_servers"`
+
+	// Local address used to reach the internet, the IP of "the" machine,
+	// empty without a default route
+	PrimaryIP string `json:"primary_ip" yaml:"primary_ip"`
+
+	// I/O counters of each network interface
+	NetIO map[string]NetIOCounters `json:"net_io" yaml:"net_io"`
+
+	// Bytes sent and received by all the interfaces of NetIO
+	TotalBytesSent uint64 `json:"total_bytes_sent" yaml:"total_bytes_sent"`
+	TotalBytesRecv uint64 `json:"total_bytes_recv" yaml:"total_bytes_recv"`
+
+	// Number of TCP connections in each state, e.g. ESTABLISHED,
+	// only read with WithConnections
+	Connections map[string]int `json:"connections" yaml:"connections"`
+
+	// Host sensor temperatures, if any are exposed
+	Temperatures []Temperature `json:"temperatures" yaml:"temperatures"`
+
+	// Status of each battery, if any
+	Batteries []Battery `json:"batteries" yaml:"batteries"`
+
+	// Processes using the most cpu and memory
+	TopCPUProcesses    []ProcessInfo `json:"top_cpu_processes" yaml:"top_cpu_processes"`
+	TopMemoryProcesses []ProcessInfo `json:"top_memory_processes" yaml:"top_memory_processes"`
+
+	// Processes whose name matches WithProcessFilter, by cpu usage,
+	// and the sum of their usage
+	MatchedProcesses      []ProcessInfo `json:"matched_processes" yaml:"matched_processes"`
+	MatchedProcessesTotal ProcessInfo   `json:"matched_processes_total" yaml:"matched_processes_total"`
+
+	// Network I/O of each network namespace by throughput,
+	// only read with WithProcessNetIO on linux
+	ProcessNetIO []ProcessNetIO `json:"process_net_io" yaml:"process_net_io"`
 }
 
-// Struct to hold cpu info
-type cpuinfo struct {
-	Index    int
-	VendorID string
-	Family   string
-	Cores    int
-	Model    string
-	Speed    string
+// CPUInfo holds information about a single cpu.
+type CPUInfo struct {
+	Index    int    `json:"index" yaml:"index"`
+	VendorID string `json:"vendor_id" yaml:"vendor_id"`
+	Family   string `json:"family" yaml:"family"`
+	Cores    int    `json:"cores" yaml:"cores"`
+	Model    string `json:"model" yaml:"model"`
+	Speed    string `json:"speed" yaml:"speed"`
+
+	// Current and maximum frequency in MHz where the OS exposes frequency
+	// scaling, e.g. to spot throttling; 0 otherwise
+	CurrentMHz float64 `json:"current_mhz" yaml:"current_mhz"`
+	MaxMHz     float64 `json:"max_mhz" yaml:"max_mhz"`
+
+	// CoreTypePerformance or CoreTypeEfficiency on hybrid cpus
+	// such as arm big.LITTLE, where the OS exposes it
+	CoreType string `json:"core_type" yaml:"core_type"`
 }
 
-// getDiskUsage returns disk usage information
-func getDiskUsage() (fs syscall.Statfs_t) {
-	syscall.Statfs("/", &fs)
-	return
-}
+// Core types of CPUInfo on hybrid cpus.
+const (
+	CoreTypePerformance = "performance"
+	CoreTypeEfficiency  = "efficiency"
+)
 
-// toHumanReadable converts bytes to human readable format
-// e.g. 1.5 GB, 25 MB
-func toHumanReadable(bytes uint64) string {
-	// if less than 1 GiB, return in MB
-	if bytes < 1024*1024*1024 {
-		return fmt.Sprintf("%.2f MB", float64(bytes)/1024/1024)
-	}
+// GoMemStats holds memory statistics of the Go runtime,
+// as opposed to the memory of the whole system.
+type GoMemStats struct {
+	Alloc     uint64 `json:"alloc" yaml:"alloc"`           // bytes of allocated heap objects
+	HeapInuse uint64 `json:"heap_inuse" yaml:"heap_inuse"` // bytes in in-use heap spans
+	Sys       uint64 `json:"sys" yaml:"sys"`               // bytes obtained from the OS
+	NumGC     uint32 `json:"num_gc" yaml:"num_gc"`         // completed GC cycles
+}
 
-	return fmt.Sprintf("%.2f GB", float64(bytes)/1024/1024/1024)
+// LoadAvg holds the 1, 5 and 15 minute system load averages.
+type LoadAvg struct {
+	Load1  float64 `json:"load1" yaml:"load1"`
+	Load5  float64 `json:"load5" yaml:"load5"`
+	Load15 float64 `json:"load15" yaml:"load15"`
 }
 
-// ReadMetrics reads metrics from the system
-// and returns a Metrics struct
-func ReadMetrics() sysMetrics {
-	m := sysMetrics{}
-	m.IPAddrs = make(map[string][]string)
+// gopsutilVersion is the version of gopsutil gonet was built with.
+var gopsutilVersion = func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
 
-	m.GoNumCPU = runtime.NumCPU()
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/shirou/gopsutil/v3" {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return ""
+}()
+
+// Subsystems reported by CollectError.
+const (
+	SubsystemDisk            = "disk"
+	SubsystemPartitions      = "partitions"
+	SubsystemDiskIO          = "disk_io"
+	SubsystemMemory          = "memory"
+	SubsystemCPU             = "cpu"
+	SubsystemCPUPercent      = "cpu_percent"
+	SubsystemCPUCounts       = "cpu_counts"
+	SubsystemPerCorePercent  = "per_core_percent"
+	SubsystemHost            = "host"
+	SubsystemLoad            = "load"
+	SubsystemNetwork         = "network"
+	SubsystemNetIO           = "net_io"
+	SubsystemGateway         = "gateway"
+	SubsystemContainer       = "container"
+	SubsystemDNS             = "dns"
+	SubsystemConnections     = "connections"
+	SubsystemTemperatures    = "temperatures"
+	SubsystemBattery         = "battery"
+	SubsystemProcesses       = "processes"
+	SubsystemFileDescriptors = "file_descriptors"
+	SubsystemProcessNetIO    = "process_net_io"
+	SubsystemHardware        = "hardware"
+	SubsystemCPUTimes        = "cpu_times"
+	SubsystemGPU             = "gpu"
+)
 
-	var memoryStats runtime.MemStats
-	runtime.ReadMemStats(&memoryStats)
+// CollectError is returned (joined with errors.Join) by ReadMetrics
+// for every subsystem whose metrics could not be read.
+type CollectError struct {
+	Subsystem string
+	Err       error
+}
 
-	// Disk usage
-	fs := getDiskUsage()
-	m.DiskSize = fs.Blocks * uint64(fs.Bsize)
-	m.DiskFree = fs.Bfree * uint64(fs.Bsize)
-	m.DiskUsage = m.DiskSize - m.DiskFree
+func (e *CollectError) Error() string {
+	return fmt.Sprintf("gonet: reading %s: %s", e.Subsystem, e.Err)
+}
 
-	// System memory
-	vmStat, err := mem.VirtualMemory()
+func (e *CollectError) Unwrap() error {
+	return e.Err
+}
 
+// failedSubsystems returns the set of subsystems that failed in err.
+func failedSubsystems(err error) map[string]bool {
+	failed := make(map[string]bool)
 	if err == nil {
-		m.TotalMemory = vmStat.Total
-		m.FreeMemory = vmStat.Free
-		m.UsedMemory = vmStat.Used
-		m.CacheMemory = vmStat.Cached
+		return failed
 	}
 
-	// cpu
-	// loop through all available cpus
-	cpuStats, err := cpu.Info()
-	if err == nil {
-		for index, c := range cpuStats {
-			m.CPUInfo = append(m.CPUInfo, cpuinfo{
-				Index:    index,
-				VendorID: c.VendorID,
-				Family:   c.Family,
-				Cores:    int(c.Cores),
-				Model:    c.ModelName,
-				Speed:    strconv.FormatFloat(c.Mhz, 'f', 2, 64) + " MHz",
-			})
+	var errs []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	} else {
+		errs = []error{err}
+	}
+
+	for _, e := range errs {
+		var ce *CollectError
+		if errors.As(e, &ce) {
+			failed[ce.Subsystem] = true
 		}
 	}
+	return failed
+}
+
+// isNotImplemented reports whether err is gopsutil's error for
+// metrics that are not implemented on the current platform.
+// The sentinel lives in an internal package so it is matched by message.
+func isNotImplemented(err error) bool {
+	return err != nil && err.Error() == "not implemented yet"
+}
+
+// withContext calls fn in a new goroutine and waits for it to return or
+// for ctx to be done, whichever comes first. If ctx is done first, fn is
+// abandoned and left to finish in the background.
+func withContext[T any](ctx context.Context, fn func(context.Context) (T, error)) (T, error) {
+	var zero T
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	type result struct {
+		v   T
+		err error
+	}
 
-	// cpu %
-	percentage, err := cpu.Percent(0, false)
+	done := make(chan result, 1)
+	go func() {
+		v, err := fn(ctx)
+		done <- result{v, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// unavailable is rendered in place of values that could not be read.
+const unavailable = "unavailable"
+
+// requiresPrivileges is rendered in place of values that could not be read
+// without elevated privileges.
+const requiresPrivileges = "requires elevated privileges"
+
+// IsPermissionError reports whether err, e.g. a *CollectError, was caused
+// by missing privileges, such as reading the connections or processes of
+// other users when not running as root.
+func IsPermissionError(err error) bool {
 	if err == nil {
-		m.CPUPercent = percentage[0]
+		return false
+	}
+
+	if errors.Is(err, os.ErrPermission) {
+		return true
+	}
+
+	// gopsutil doesn't always wrap the errors of the OS
+	msg := err.Error()
+	return strings.Contains(msg, "permission denied") || strings.Contains(msg, "operation not permitted")
+}
+
+// unavailable returns the text rendered in place of the values of a
+// subsystem that could not be read.
+func (m Metrics) unavailable(subsystem string) string {
+	if IsPermissionError(m.Errors[subsystem]) {
+		return requiresPrivileges
+	}
+	return unavailable
+}
+
+// unavailableRow returns a table row with n cells marking subsystem unavailable.
+func (m Metrics) unavailableRow(subsystem string, n int) table.Row {
+	row := make(table.Row, n)
+	for i := range row {
+		row[i] = m.unavailable(subsystem)
+	}
+	return row
+}
+
+// Units selects how byte counts are formatted in tables.
+type Units int
+
+const (
+	// UnitsBinary formats bytes in IEC units (KiB, MiB, GiB...), each 1024 times the previous.
+	UnitsBinary Units = iota
+
+	// UnitsDecimal formats bytes in SI units (kB, MB, GB...), each 1000 times the previous.
+	UnitsDecimal
+)
+
+var (
+	binaryUnits  = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	decimalUnits = []string{"B", "kB", "MB", "GB", "TB", "PB"}
+)
+
+// defaultPrecision is the number of decimals of human readable byte counts.
+const defaultPrecision = 2
+
+// humanReadable converts bytes to human readable format using the largest
+// unit the value reaches, with precision decimals, e.g. 1.50 GiB, 25.00 MiB
+func humanReadable(bytes uint64, units Units, precision int) string {
+	names, base := binaryUnits, 1024.0
+	if units == UnitsDecimal {
+		names, base = decimalUnits, 1000.0
+	}
+
+	if float64(bytes) < base {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	value := float64(bytes)
+	unit := 0
+
+	// compare the rounded value so that 1023.999 KiB shows as 1.00 MiB, not 1024.00 KiB
+	scale := math.Pow10(precision)
+	for unit < len(names)-1 && math.Round(value*scale)/scale >= base {
+		value /= base
+		unit++
+	}
+	return fmt.Sprintf("%.*f %s", precision, value, names[unit])
+}
+
+// percent returns part as a percentage of total, or 0 if total is 0.
+func percent(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total) * 100
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatDuration formats d in days, hours and minutes, e.g. 3d 4h 12m.
+// Durations under a minute are formatted in seconds, e.g. 42s.
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int64(d.Seconds()))
+	}
+
+	days := d / (24 * time.Hour)
+	hours := d % (24 * time.Hour) / time.Hour
+	minutes := d % time.Hour / time.Minute
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+
+	if hours > 0 {
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// String returns a one-line summary of m for logging, e.g.
+// cpu=12.3% mem=4.2/16.0 GiB disk=120.0/500.0 GiB host=web01
+func (m Metrics) String() string {
+	b := make([]byte, 0, 96)
+	b = append(b, "cpu="...)
+	b = strconv.AppendFloat(b, m.CPUPercent, 'f', 1, 64)
+	b = append(b, "% mem="...)
+	b = appendUsage(b, m.UsedMemory, m.TotalMemory)
+	b = append(b, " disk="...)
+	b = appendUsage(b, m.DiskUsage, m.DiskSize)
+	b = append(b, " host="...)
+	b = append(b, m.Hostname...)
+	return string(b)
+}
+
+// appendUsage appends used/total to b in the binary unit of total, e.g. 4.2/16.0 GiB.
+func appendUsage(b []byte, used, total uint64) []byte {
+	unit, scale := 0, 1.0
+	for unit < len(binaryUnits)-1 && float64(total)/scale >= 1024 {
+		scale *= 1024
+		unit++
+	}
+
+	b = strconv.AppendFloat(b, float64(used)/scale, 'f', 1, 64)
+	b = append(b, '/')
+	b = strconv.AppendFloat(b, float64(total)/scale, 'f', 1, 64)
+	b = append(b, ' ')
+	return append(b, binaryUnits[unit]...)
+}
+
+// ReadMetricsForPath reads metrics from the system, reporting
+// the disk usage of the filesystem at path.
+func ReadMetricsForPath(path string) (Metrics, error) {
+	return ReadMetrics(WithDiskPath(path))
+}
+
+// ReadMetricsWithInterval reads metrics from the system, sampling
+// cpu usage over d. See WithCPUInterval.
+func ReadMetricsWithInterval(d time.Duration) (Metrics, error) {
+	return ReadMetrics(WithCPUInterval(d))
+}
+
+// ReadMetrics reads metrics from the system and returns a Metrics struct.
+// If some subsystems could not be read, the partially filled metrics are
+// returned along with an error joining a *CollectError for each failure.
+func ReadMetrics(opts ...Option) (Metrics, error) {
+	return ReadMetricsContext(context.Background(), opts...)
+}
+
+// ReadMetricsContext is like ReadMetrics but stops collecting when ctx is done,
+// in which case the error returned also matches ctx.Err() with errors.Is.
+// Each subsystem is read in its own goroutine so that a call stuck in the
+// kernel, e.g. on a hung NFS mount, is abandoned rather than waited for.
+func ReadMetricsContext(ctx context.Context, opts ...Option) (Metrics, error) {
+	o := newOptions(opts)
+
+	m := Metrics{SchemaVersion: SchemaVersion, CollectedAt: time.Now()}
+	m.MacAddrs = make(map[string]string)
+	m.IPAddrs = make(map[string][]string)
+	m.IPv4Addrs = make(map[string][]string)
+	m.IPv6Addrs = make(map[string][]string)
+	m.MTUs = make(map[string]int)
+	m.LinkSpeeds = make(map[string]int)
+	m.GoNumCPU = runtime.NumCPU()
+	m.GOOS = runtime.GOOS
+	m.GOARCH = runtime.GOARCH
+	m.GoVersion = runtime.Version()
+	m.NumGoroutine = runtime.NumGoroutine()
+
+	var memoryStats runtime.MemStats
+	runtime.ReadMemStats(&memoryStats)
+	m.GoMemory = GoMemStats{
+		Alloc:     memoryStats.Alloc,
+		HeapInuse: memoryStats.HeapInuse,
+		Sys:       memoryStats.Sys,
+		NumGC:     memoryStats.NumGC,
+	}
+
+	var c Collector = systemCollector{o}
+	if o.collector != nil {
+		c = o.collector
 	} else {
-		fmt.Fprintf(os.Stderr, "error getting CPU Percent Usage: %s\n", err)
+		m.GopsutilVersion = gopsutilVersion
 	}
 
-	// hostStats
-	hostStat, err := host.Info()
-	if err == nil {
-		m.Hostname = hostStat.Hostname
-		m.RunningProcesses = hostStat.Procs
-		m.Platform = hostStat.Platform
-		m.PlatformVersion = hostStat.PlatformVersion
+	// Each subsystem fills its own fields of m, so they are safe to read
+	// concurrently. errs is indexed by subsystem to keep a stable order.
+	var subsystems []string
+	for _, subsystem := range Subsystems() {
+		if o.enabled(subsystem) {
+			subsystems = append(subsystems, subsystem)
+		}
 	}
 
-	inetfStat, err := net.Interfaces()
+	errs := make([]error, len(subsystems))
+	var wg sync.WaitGroup
+	for i, subsystem := range subsystems {
+		wg.Add(1)
+		go func(i int, subsystem string) {
+			defer wg.Done()
+			errs[i] = c.Collect(ctx, subsystem, &m)
+		}(i, subsystem)
+	}
+	wg.Wait()
 
-	if err == nil && len(inetfStat) > 0 {
-		for _, iface := range inetfStat {
-			if iface.HardwareAddr != "" {
-				m.MacAddr = iface.HardwareAddr
-			}
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
 
-			for _, addr := range iface.Addrs {
-				m.IPAddrs[iface.Name] = append(m.IPAddrs[iface.Name], addr.Addr)
-			}
+		if m.Errors == nil {
+			m.Errors = make(SubsystemErrors)
 		}
+		m.Errors[subsystems[i]] = err
+		errs[i] = &CollectError{subsystems[i], err}
+	}
+	m.redact(o.redaction)
+
+	if err := ctx.Err(); err != nil {
+		errs = append([]error{err}, errs...)
 	}
+	return m, errors.Join(errs...)
+}
 
-	return m
+// WriteMetrics writes metrics to the given writer as tables.
+// If writer is nil, it will write to stdout
+//
+// The sections that could not be read are marked unavailable, in which case
+// the collection error from ReadMetrics is returned unless writing failed.
+func WriteMetrics(writer io.Writer, opts ...Option) error {
+	if writer == nil {
+		writer = os.Stdout
+	}
+	return WriteMetricsAs(writer, FormatTable, opts...)
 }
 
-// WriteMetrics writes metrics to the given writer.
+// RenderMetrics writes metrics obtained elsewhere, e.g. decoded from JSON
+// or returned by ReadMetricsSSH, to the given writer as tables, without
+// reading anything from the system. The optional sections, such as disk
+// I/O, connections and processes, and the rate columns are rendered when
+// metrics has data for them, so they don't need the options they were read
+// with; passing those options also renders them when empty. Sections of
+// the subsystems in metrics.Errors are marked unavailable, and the fields
+// selected by WithRedaction masked.
 // If writer is nil, it will write to stdout
-func WriteMetrics(writer io.Writer) {
+// RenderMetrics returns the first write error.
+func RenderMetrics(writer io.Writer, metrics Metrics, opts ...Option) error {
 	if writer == nil {
 		writer = os.Stdout
 	}
 
-	// Read the metrics
-	metrics := ReadMetrics()
-	fmt.Fprintln(writer)
-
-	// print cpu metrics and usage
-	t := table.NewWriter()
-	t.SetOutputMirror(writer)
-	t.AppendHeader(table.Row{"CPUs", "CPU Usage"})
-	t.AppendRow(table.Row{metrics.GoNumCPU, fmt.Sprintf("%.2f%%", metrics.CPUPercent)})
-
-	t.SetStyle(table.StyleColoredBlackOnBlueWhite)
-	t.SetTitle("%s", "CPU Usage")
-	t.Render()
-	fmt.Fprintln(writer)
-
-	// print architecture and stats for each cpu
-	t1 := table.NewWriter()
-	t1.SetTitle("%s", "CPU INFO")
-	t1.SetOutputMirror(writer)
-	t1.AppendHeader(table.Row{"#", "Vendor ID", "Family", "Cores", "Model", "Speed"})
-	for _, c := range metrics.CPUInfo {
-		t1.AppendRow(table.Row{
-			c.Index, c.VendorID, c.Family, c.Cores, c.Model, c.Speed,
-		})
-	}
-
-	t1.SetStyle(table.StyleColoredBright)
-	t1.Render()
-	fmt.Fprintln(writer)
-
-	// print disk usage
-	t2 := table.NewWriter()
-	t2.SetTitle("%s", "Disk usage")
-	t2.SetOutputMirror(writer)
-	t2.AppendHeader(table.Row{"Disk Size", "Disk Free", "Disk Usage", "Disk Usage %"})
-	t2.AppendRows([]table.Row{
-		{toHumanReadable(metrics.DiskSize), toHumanReadable(metrics.DiskFree), toHumanReadable(metrics.DiskUsage),
-			fmt.Sprintf("%.1f%%", float64(metrics.DiskUsage)/float64(metrics.DiskSize)*100)},
-	})
-	t2.SetStyle(table.StyleColoredBright)
-	t2.Render()
-	fmt.Fprintln(writer)
-
-	// Print system memory usage
-	t3 := table.NewWriter()
-	t3.SetTitle("%s", "System Memory")
-	t3.SetOutputMirror(writer)
-	t3.AppendHeader(table.Row{"#", "Total Memory", "Free Memory", "Used Memory", "Cache Memory"})
-	t3.AppendRows([]table.Row{
-		{1, toHumanReadable(metrics.TotalMemory), toHumanReadable(metrics.FreeMemory), toHumanReadable(metrics.UsedMemory), toHumanReadable(metrics.CacheMemory)},
-	})
-	t3.SetStyle(table.StyleColoredBright)
-	t3.Render()
-	fmt.Fprintln(writer)
-
-	// Print hostname, platform, platform version, running processes
-	t4 := table.NewWriter()
-	t4.SetTitle("%s", "Platform/System info:")
-	t4.SetOutputMirror(writer)
-	t4.AppendHeader(table.Row{"Hostname", "Running Processes", "Platform", "Platform Version"})
-	t4.AppendRows([]table.Row{
-		{metrics.Hostname, metrics.RunningProcesses, metrics.Platform, metrics.PlatformVersion},
-	})
-	t4.SetStyle(table.StyleColoredBright)
-	t4.Render()
-	fmt.Fprintln(writer)
-
-	// Print MAC Address
-	t5 := table.NewWriter()
-	t5.SetTitle("%s", "Mac Address:")
-	t5.SetOutputMirror(writer)
-	t5.AppendHeader(table.Row{"Mac Address"})
-	t5.AppendRows([]table.Row{
-		{metrics.MacAddr},
-	})
-	t5.SetStyle(table.StyleColoredBright)
-	t5.Render()
-
-	fmt.Fprintln(writer)
-
-	// Print Network interfaces and IP addresses
-	t6 := table.NewWriter()
-	t6.SetTitle("%s", "Network interfaces:")
-	t6.SetOutputMirror(writer)
-	t6.AppendHeader(table.Row{"Interface", "IP Addresses"})
-	for iface, ipaddrs := range metrics.IPAddrs {
-		t6.AppendRows([]table.Row{
-			{iface, strings.Join(ipaddrs, ", ")},
-		})
-	}
-
-	t6.SetStyle(table.StyleColoredBright)
-	t6.Render()
+	o := newOptions(opts)
+	metrics.redact(o.redaction)
+	return renderMetrics(writer, metrics, metrics.Errors.failed(), o)
+}
+
+// WriteMetricsWithStyle writes metrics to the given writer,
+// rendering every table in the given style.
+func WriteMetricsWithStyle(writer io.Writer, style table.Style, opts ...Option) error {
+	return WriteMetrics(writer, append([]Option{WithTableStyle(style)}, opts...)...)
+}
+
+// WriteMetricsMulti reads metrics once and writes each section in writers
+// as tables to its own writer, e.g. to route the cpu and disk tables to
+// different logs. Sections not in writers are skipped, so WithSections
+// has no effect. Colors and widths are decided per writer.
+//
+// Every writer is written to even if another fails, and the first write
+// error is returned, otherwise the collection error from ReadMetrics.
+func WriteMetricsMulti(writers map[Section]io.Writer, opts ...Option) error {
+	metrics, err := ReadMetrics(opts...)
+	failed := failedSubsystems(err)
+
+	var werr error
+	for _, s := range sections {
+		w, ok := writers[s.section]
+		if !ok {
+			continue
+		}
+
+		o := newOptions(opts)
+		o.sections = map[Section]bool{s.section: true}
+		if e := renderMetrics(w, metrics, failed, o); e != nil && werr == nil {
+			werr = e
+		}
+	}
+
+	if werr != nil {
+		return werr
+	}
+	return err
 }
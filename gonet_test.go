@@ -0,0 +1,96 @@
+package gonet
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanReadable(t *testing.T) {
+	tests := []struct {
+		bytes uint64
+		units Units
+		want  string
+	}{
+		{0, UnitsBinary, "0 B"},
+		{1023, UnitsBinary, "1023 B"},
+		{1024, UnitsBinary, "1.00 KiB"},
+		{1536, UnitsBinary, "1.50 KiB"},
+		{1<<20 - 1, UnitsBinary, "1.00 MiB"}, // 1023.999 KiB rounds up
+		{1 << 30, UnitsBinary, "1.00 GiB"},
+		{1 << 40, UnitsBinary, "1.00 TiB"},
+		{1<<40 + 1<<39, UnitsBinary, "1.50 TiB"},
+		{1 << 50, UnitsBinary, "1.00 PiB"},
+		{1 << 60, UnitsBinary, "1024.00 PiB"},
+		{999, UnitsDecimal, "999 B"},
+		{1000, UnitsDecimal, "1.00 kB"},
+		{999_999, UnitsDecimal, "1.00 MB"},
+		{1e12, UnitsDecimal, "1.00 TB"},
+		{1e15, UnitsDecimal, "1.00 PB"},
+	}
+
+	for _, tt := range tests {
+		if got := humanReadable(tt.bytes, tt.units, defaultPrecision); got != tt.want {
+			t.Errorf("humanReadable(%d, %d, %d) = %q, want %q", tt.bytes, tt.units, defaultPrecision, got, tt.want)
+		}
+	}
+}
+
+func TestHumanReadablePrecision(t *testing.T) {
+	tests := []struct {
+		precision int
+		want      string
+	}{
+		{-1, "2 GiB"}, // clamped to 0
+		{0, "2 GiB"},
+		{1, "1.5 GiB"},
+		{2, "1.50 GiB"},
+		{3, "1.50 GiB"}, // clamped to 2
+	}
+
+	for _, tt := range tests {
+		o := newOptions([]Option{WithPrecision(tt.precision)})
+		if got := humanReadable(1<<30+1<<29, UnitsBinary, o.precision); got != tt.want {
+			t.Errorf("WithPrecision(%d): humanReadable(1.5 GiB) = %q, want %q", tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		part, total uint64
+		want        float64
+	}{
+		{0, 0, 0},
+		{5, 0, 0},
+		{0, 10, 0},
+		{5, 10, 50},
+		{10, 10, 100},
+	}
+
+	for _, tt := range tests {
+		if got := percent(tt.part, tt.total); got != tt.want {
+			t.Errorf("percent(%d, %d) = %v, want %v", tt.part, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{59 * time.Second, "59s"},
+		{59*time.Second + 999*time.Millisecond, "59s"},
+		{time.Minute, "1m"},
+		{2*time.Hour + 45*time.Minute, "2h 45m"},
+		{24 * time.Hour, "1d 0h 0m"},
+		{72*time.Hour + 3*time.Minute, "3d 0h 3m"},
+	}
+
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.want {
+			t.Errorf("formatDuration(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
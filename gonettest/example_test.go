@@ -0,0 +1,50 @@
+package gonettest_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/abiiranathan/gonet"
+	"github.com/abiiranathan/gonet/gonettest"
+)
+
+func ExampleFakeCollector() {
+	fake := &gonettest.FakeCollector{
+		Metrics: gonet.Metrics{
+			TotalMemory:       16 << 30,
+			FreeMemory:        4 << 30,
+			UsedMemory:        10 << 30,
+			CacheMemory:       2 << 30,
+			MemoryUsedPercent: 62.5,
+		},
+		Errors: map[string]error{gonet.SubsystemPartitions: errors.New("no mounts")},
+	}
+
+	metrics, err := gonet.ReadMetrics(gonet.WithCollector(fake))
+	fmt.Println(err)
+	metrics.CollectedAt = time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	gonet.RenderMetrics(os.Stdout, metrics, gonet.WithNoColor(), gonet.WithSections(gonet.SectionDisk, gonet.SectionMemory))
+	// Output:
+	// gonet: reading partitions: no mounts
+	//
+	// Collected at Fri, 01 Mar 2024 12:30:00 UTC
+	//
+	// ┌──────────────────────────────────────────────────────────────────────────────────────────────────┐
+	// │ Disk Usage                                                                                       │
+	// ├─────────────┬─────────────┬─────────────┬─────────────┬─────────────┬──────────────┬─────────────┤
+	// │ MOUNTPOINT  │ FSTYPE      │ DISK SIZE   │ DISK FREE   │ DISK USAGE  │ DISK USAGE % │ USAGE       │
+	// ├─────────────┼─────────────┼─────────────┼─────────────┼─────────────┼──────────────┼─────────────┤
+	// │ unavailable │ unavailable │ unavailable │ unavailable │ unavailable │ unavailable  │ unavailable │
+	// └─────────────┴─────────────┴─────────────┴─────────────┴─────────────┴──────────────┴─────────────┘
+	//
+	// ┌─────────────────────────────────────────────────────────────────────────────────────┐
+	// │ System Memory                                                                       │
+	// ├───┬──────────────┬─────────────┬─────────────┬──────────────┬────────┬──────────────┤
+	// │ # │ TOTAL MEMORY │ FREE MEMORY │ USED MEMORY │ CACHE MEMORY │ USED % │ USAGE        │
+	// ├───┼──────────────┼─────────────┼─────────────┼──────────────┼────────┼──────────────┤
+	// │ 1 │ 16.00 GiB    │ 4.00 GiB    │ 10.00 GiB   │ 2.00 GiB     │ 62.5%  │ [######----] │
+	// └───┴──────────────┴─────────────┴─────────────┴──────────────┴────────┴──────────────┘
+}
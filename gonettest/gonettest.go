@@ -0,0 +1,99 @@
+// Package gonettest provides a fake gonet.Collector for testing code built
+// on gonet, such as alerting on thresholds, against known metrics.
+//
+//	fake := &gonettest.FakeCollector{
+//		Metrics: gonet.Metrics{CPUPercent: 97, MemoryUsedPercent: 40},
+//		Errors:  map[string]error{gonet.SubsystemBattery: errors.New("no battery")},
+//	}
+//	metrics, err := gonet.ReadMetrics(gonet.WithCollector(fake))
+//
+// Optional subsystems, e.g. gonet.SubsystemDiskIO, are only collected when
+// their option, e.g. gonet.WithDiskIO, is passed to gonet.ReadMetrics.
+// The collection time and the Go runtime fields are still set by
+// ReadMetrics; set metrics.CollectedAt before rendering for a deterministic
+// output, e.g. with gonet.RenderMetrics and gonet.WithNoColor.
+package gonettest
+
+import (
+	"context"
+
+	"github.com/abiiranathan/gonet"
+)
+
+// FakeCollector is a gonet.Collector that returns canned metrics.
+type FakeCollector struct {
+	// Metrics whose fields are copied by the subsystem that owns them,
+	// e.g. CPUPercent by gonet.SubsystemCPUPercent
+	Metrics gonet.Metrics
+
+	// Errors returned for subsystems instead of their metrics
+	Errors map[string]error
+}
+
+// Collect copies the fields of f.Metrics owned by subsystem into m,
+// or returns the error of subsystem in f.Errors.
+func (f *FakeCollector) Collect(ctx context.Context, subsystem string, m *gonet.Metrics) error {
+	if err := f.Errors[subsystem]; err != nil {
+		return err
+	}
+
+	c := &f.Metrics
+	switch subsystem {
+	case gonet.SubsystemDisk:
+		m.DiskPath, m.DiskSize, m.DiskFree, m.DiskUsage = c.DiskPath, c.DiskSize, c.DiskFree, c.DiskUsage
+		m.DiskUsedPercent = c.DiskUsedPercent
+	case gonet.SubsystemPartitions:
+		m.Disks = c.Disks
+	case gonet.SubsystemDiskIO:
+		m.DiskIO = c.DiskIO
+	case gonet.SubsystemMemory:
+		m.TotalMemory, m.FreeMemory, m.UsedMemory, m.CacheMemory = c.TotalMemory, c.FreeMemory, c.UsedMemory, c.CacheMemory
+		m.MemoryUsedPercent = c.MemoryUsedPercent
+	case gonet.SubsystemContainer:
+		m.InContainer, m.ContainerMemLimit, m.ContainerCPUQuota = c.InContainer, c.ContainerMemLimit, c.ContainerCPUQuota
+	case gonet.SubsystemCPU:
+		m.CPUInfo = c.CPUInfo
+	case gonet.SubsystemCPUPercent:
+		m.CPUPercent = c.CPUPercent
+	case gonet.SubsystemCPUCounts:
+		m.LogicalCores, m.PhysicalCores = c.LogicalCores, c.PhysicalCores
+	case gonet.SubsystemPerCorePercent:
+		m.PerCorePercent = c.PerCorePercent
+	case gonet.SubsystemHost:
+		m.Hostname, m.RunningProcesses, m.Platform, m.PlatformVersion = c.Hostname, c.RunningProcesses, c.Platform, c.PlatformVersion
+		m.Uptime, m.BootTime = c.Uptime, c.BootTime
+		m.VirtSystem, m.VirtRole = c.VirtSystem, c.VirtRole
+	case gonet.SubsystemCPUTimes:
+		m.CPUTimes = c.CPUTimes
+	case gonet.SubsystemGPU:
+		m.GPUs = c.GPUs
+	case gonet.SubsystemHardware:
+		m.Hardware = c.Hardware
+	case gonet.SubsystemLoad:
+		m.LoadAvg = c.LoadAvg
+	case gonet.SubsystemNetwork:
+		m.MacAddr, m.MacAddrs, m.IPAddrs = c.MacAddr, c.MacAddrs, c.IPAddrs
+		m.IPv4Addrs, m.IPv6Addrs = c.IPv4Addrs, c.IPv6Addrs
+		m.MTUs, m.LinkSpeeds = c.MTUs, c.LinkSpeeds
+	case gonet.SubsystemNetIO:
+		m.NetIO, m.TotalBytesSent, m.TotalBytesRecv = c.NetIO, c.TotalBytesSent, c.TotalBytesRecv
+	case gonet.SubsystemGateway:
+		m.DefaultGateway, m.PrimaryIP = c.DefaultGateway, c.PrimaryIP
+	case gonet.SubsystemDNS:
+		m.DNSServers = c.DNSServers
+	case gonet.SubsystemConnections:
+		m.Connections = c.Connections
+	case gonet.SubsystemTemperatures:
+		m.Temperatures = c.Temperatures
+	case gonet.SubsystemBattery:
+		m.Batteries = c.Batteries
+	case gonet.SubsystemProcesses:
+		m.TopCPUProcesses, m.TopMemoryProcesses = c.TopCPUProcesses, c.TopMemoryProcesses
+		m.MatchedProcesses, m.MatchedProcessesTotal = c.MatchedProcesses, c.MatchedProcessesTotal
+	case gonet.SubsystemProcessNetIO:
+		m.ProcessNetIO = c.ProcessNetIO
+	case gonet.SubsystemFileDescriptors:
+		m.OpenFDs, m.MaxFDs = c.OpenFDs, c.MaxFDs
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package gonettest_test
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/abiiranathan/gonet"
+	"github.com/abiiranathan/gonet/gonettest"
+)
+
+// recorder is a FakeCollector that records the subsystems it collects.
+type recorder struct {
+	gonettest.FakeCollector
+
+	mu        sync.Mutex
+	collected map[string]bool
+}
+
+func (r *recorder) Collect(ctx context.Context, subsystem string, m *gonet.Metrics) error {
+	r.mu.Lock()
+	r.collected[subsystem] = true
+	r.mu.Unlock()
+	return r.FakeCollector.Collect(ctx, subsystem, m)
+}
+
+func TestFormatsSkipProcesses(t *testing.T) {
+	tests := []struct {
+		format    gonet.Format
+		processes bool
+	}{
+		{gonet.FormatTable, true},
+		{gonet.FormatJSON, true},
+		{gonet.FormatKV, true},
+		{gonet.FormatCSV, false},
+		{gonet.FormatPrometheus, false},
+		{gonet.FormatInflux, false},
+	}
+
+	for _, tt := range tests {
+		r := &recorder{collected: make(map[string]bool)}
+		if err := gonet.WriteMetricsAs(io.Discard, tt.format, gonet.WithCollector(r)); err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+
+		if r.collected[gonet.SubsystemProcesses] != tt.processes {
+			t.Errorf("%s: processes collected = %v, want %v", tt.format, r.collected[gonet.SubsystemProcesses], tt.processes)
+		}
+	}
+
+	r := &recorder{collected: make(map[string]bool)}
+	path := filepath.Join(t.TempDir(), "metrics.influx")
+	if err := gonet.WriteMetricsToFile(path, gonet.FormatInflux, gonet.WithCollector(r)); err != nil {
+		t.Fatal(err)
+	}
+	if r.collected[gonet.SubsystemProcesses] {
+		t.Error("WriteMetricsToFile: processes collected for influx")
+	}
+
+	r = &recorder{collected: make(map[string]bool)}
+	if err := gonet.WriteInfluxLineProtocol(io.Discard, nil, gonet.WithCollector(r)); err != nil {
+		t.Fatal(err)
+	}
+	if r.collected[gonet.SubsystemProcesses] {
+		t.Error("WriteInfluxLineProtocol: processes collected")
+	}
+
+	// asking for processes still reads them
+	r = &recorder{collected: make(map[string]bool)}
+	if err := gonet.WriteMetricsAs(io.Discard, gonet.FormatInflux, gonet.WithCollector(r), gonet.WithTopProcesses(3)); err != nil {
+		t.Fatal(err)
+	}
+	if !r.collected[gonet.SubsystemProcesses] {
+		t.Error("WithTopProcesses(3): processes not collected for influx")
+	}
+}
@@ -0,0 +1,77 @@
+package gonet
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// GPU holds the usage of an NVIDIA GPU, as reported by nvidia-smi.
+// Values the GPU doesn't report are zero.
+type GPU struct {
+	Index int    `json:"index" yaml:"index"`
+	Name  string `json:"name" yaml:"name"`
+
+	UtilizationPercent float64 `json:"utilization_percent" yaml:"utilization_percent"`
+	MemoryUsed         uint64  `json:"memory_used" yaml:"memory_used"`
+	MemoryTotal        uint64  `json:"memory_total" yaml:"memory_total"`
+
+	// In degrees Celsius
+	Temperature float64 `json:"temperature" yaml:"temperature"`
+}
+
+// gpuQuery are the fields queried from nvidia-smi, in the order of its columns.
+const gpuQuery = "index,name,utilization.gpu,memory.used,memory.total,temperature.gpu"
+
+// getGPUs returns the NVIDIA GPUs reported by nvidia-smi, and none
+// without an error if nvidia-smi is not installed.
+func getGPUs(ctx context.Context) ([]GPU, error) {
+	path, err := exec.LookPath("nvidia-smi")
+	if err != nil {
+		return nil, nil
+	}
+
+	out, err := exec.CommandContext(ctx, path, "--query-gpu="+gpuQuery, "--format=csv,noheader,nounits").Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return nil, fmt.Errorf("nvidia-smi: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+
+	r := csv.NewReader(strings.NewReader(string(out)))
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	// unsupported values are reported as [N/A] or [Not Supported]
+	number := func(s string) float64 {
+		f, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return f
+	}
+
+	const mib = 1 << 20
+	var gpus []GPU
+	for _, rec := range records {
+		if len(rec) != strings.Count(gpuQuery, ",")+1 {
+			continue
+		}
+
+		gpus = append(gpus, GPU{
+			Index:              int(number(rec[0])),
+			Name:               strings.TrimSpace(rec[1]),
+			UtilizationPercent: number(rec[2]),
+			MemoryUsed:         uint64(number(rec[3]) * mib),
+			MemoryTotal:        uint64(number(rec[4]) * mib),
+			Temperature:        number(rec[5]),
+		})
+	}
+	return gpus, nil
+}
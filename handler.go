@@ -0,0 +1,162 @@
+package gonet
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Content types served by MetricsHandler.
+const (
+	contentTypeJSON       = "application/json; charset=utf-8"
+	contentTypePrometheus = "text/plain; version=0.0.4; charset=utf-8"
+	contentTypeText       = "text/plain; charset=utf-8"
+	contentTypeHTML       = "text/html; charset=utf-8"
+	contentTypeCSV        = "text/csv; charset=utf-8"
+	contentTypeYAML       = "application/yaml; charset=utf-8"
+	contentTypeMarkdown   = "text/markdown; charset=utf-8"
+)
+
+// contentTypes are the content types of the formats served by MetricsHandler.
+var contentTypes = map[Format]string{
+	FormatTable:      contentTypeText,
+	FormatJSON:       contentTypeJSON,
+	FormatCSV:        contentTypeCSV,
+	FormatYAML:       contentTypeYAML,
+	FormatMarkdown:   contentTypeMarkdown,
+	FormatHTML:       contentTypeHTML,
+	FormatPrometheus: contentTypePrometheus,
+	FormatKV:         contentTypeText,
+	FormatInflux:     contentTypeText,
+}
+
+// mediaTypes are the formats negotiated by MetricsHandler for each media
+// type of the Accept header. text/plain is the prometheus exposition format
+// when it has a version parameter, as in prometheus scrapes, and the tables
+// otherwise.
+var mediaTypes = map[string]Format{
+	"application/json":             FormatJSON,
+	"application/openmetrics-text": FormatPrometheus,
+	"text/plain":                   FormatTable,
+	"text/html":                    FormatHTML,
+	"text/csv":                     FormatCSV,
+	"application/yaml":             FormatYAML,
+	"application/x-yaml":           FormatYAML,
+	"text/yaml":                    FormatYAML,
+	"text/markdown":                FormatMarkdown,
+}
+
+// negotiateFormat returns the format of the media type of accept, an Accept
+// header, with the highest quality, and JSON for wildcards or if none is
+// served. Media types of equal quality are picked in the order listed.
+func negotiateFormat(accept string) Format {
+	format, best := FormatJSON, 0.0
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(mediaRange)
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= best {
+			continue
+		}
+
+		f, ok := mediaTypes[mediaType]
+		switch {
+		case mediaType == "text/plain" && params["version"] != "":
+			f, ok = FormatPrometheus, true
+		case strings.HasSuffix(mediaType, "/*"):
+			f, ok = FormatJSON, true
+		}
+
+		if ok {
+			format, best = f, q
+		}
+	}
+	return format
+}
+
+// MetricsHandler returns an http.Handler that serves fresh metrics on every request.
+//
+// The format is negotiated from the Accept header: application/json serves
+// JSON, text/plain the tables and a prometheus scrape (text/plain with a
+// version, or application/openmetrics-text) the prometheus text exposition
+// format; text/html, text/csv, application/yaml and text/markdown are also
+// served. JSON is served by default. The format query parameter, as accepted
+// by ParseFormat, e.g. ?format=prometheus, takes precedence over the header.
+//
+//	mux.Handle("/metrics", gonet.MetricsHandler())
+func MetricsHandler(opts ...Option) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format := negotiateFormat(r.Header.Get("Accept"))
+		if name := r.URL.Query().Get("format"); name != "" {
+			var err error
+			if format, err = ParseFormat(name); err != nil {
+				http.Error(w, "unsupported format: "+name, http.StatusBadRequest)
+				return
+			}
+		}
+
+		opts := format.readOptions(opts)
+		metrics, err := ReadMetricsContext(r.Context(), opts...)
+		w.Header().Set("Content-Type", contentTypes[format])
+		w.Header().Add("Vary", "Accept")
+		format.write(w, metrics, failedSubsystems(err), newOptions(opts))
+	})
+}
+
+// healthResponse is the JSON body served by HealthHandler.
+type healthResponse struct {
+	Status   string   `json:"status"`
+	Breaches []Breach `json:"breaches,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// HealthHandler returns an http.Handler that checks fresh metrics against t
+// on every request, e.g. for kubernetes probes and load balancer health
+// checks. It responds 200 OK when no metric is above its limit, and 503
+// Service Unavailable when some are or when the cpu, memory or disk usage
+// could not be read. The JSON body has the status, ok, warning, critical
+// or unknown, and the breaches:
+//
+//	{"status":"critical","breaches":[{"metric":"disk:/","limit":90,"value":97.2,"critical":true}]}
+//
+// Processes are not listed unless asked for with WithTopProcesses,
+// to keep the checks fast.
+func HealthHandler(t Thresholds, opts ...Option) http.Handler {
+	opts = append([]Option{WithTopProcesses(0)}, opts...)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metrics, err := ReadMetricsContext(r.Context(), opts...)
+
+		code, resp := http.StatusOK, healthResponse{Status: "ok"}
+		failed := failedSubsystems(err)
+		for _, subsystem := range []string{SubsystemCPUPercent, SubsystemMemory, SubsystemDisk} {
+			if failed[subsystem] {
+				code, resp = http.StatusServiceUnavailable, healthResponse{Status: "unknown", Error: err.Error()}
+			}
+		}
+
+		if code == http.StatusOK {
+			resp.Breaches = metrics.CheckThresholds(t)
+			for _, b := range resp.Breaches {
+				if b.Critical {
+					code, resp.Status = http.StatusServiceUnavailable, "critical"
+				} else if resp.Status == "ok" {
+					resp.Status = "warning"
+				}
+			}
+		}
+
+		w.Header().Set("Content-Type", contentTypeJSON)
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(resp)
+	})
+}
@@ -0,0 +1,16 @@
+package gonet
+
+// Hardware identifies the machine, as reported by its firmware.
+// Fields the firmware doesn't report, or that can't be read,
+// e.g. the serial number when not running as root, are empty.
+// On cloud VMs the product is often the instance type.
+type Hardware struct {
+	Vendor  string `json:"vendor" yaml:"vendor"`
+	Product string `json:"product" yaml:"product"`
+	Version string `json:"version" yaml:"version"`
+	Serial  string `json:"serial" yaml:"serial"`
+
+	BIOSVendor  string `json:"bios_vendor" yaml:"bios_vendor"`
+	BIOSVersion string `json:"bios_version" yaml:"bios_version"`
+	BIOSDate    string `json:"bios_date" yaml:"bios_date"`
+}
@@ -0,0 +1,19 @@
+//go:build linux
+
+package gonet
+
+const dmiPath = "/sys/class/dmi/id"
+
+// getHardware returns the DMI identification of the machine. Machines
+// without DMI, such as most arm boards, have none and give no error.
+func getHardware() Hardware {
+	return Hardware{
+		Vendor:      readSysString(dmiPath, "sys_vendor"),
+		Product:     readSysString(dmiPath, "product_name"),
+		Version:     readSysString(dmiPath, "product_version"),
+		Serial:      readSysString(dmiPath, "product_serial"),
+		BIOSVendor:  readSysString(dmiPath, "bios_vendor"),
+		BIOSVersion: readSysString(dmiPath, "bios_version"),
+		BIOSDate:    readSysString(dmiPath, "bios_date"),
+	}
+}
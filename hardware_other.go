@@ -0,0 +1,8 @@
+//go:build !linux
+
+package gonet
+
+// getHardware is only implemented on linux.
+func getHardware() Hardware {
+	return Hardware{}
+}
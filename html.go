@@ -0,0 +1,67 @@
+package gonet
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// htmlHeader opens the page written by WriteMetricsHTML.
+// It takes the page title as its only argument.
+const htmlHeader = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>%s</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em; color: #222; }
+h2 { font-size: 1.1em; margin: 1.5em 0 0.5em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; }
+th { background: #f0f0f0; }
+tr:nth-child(even) td { background: #fafafa; }
+.generated { color: #777; font-size: 0.9em; }
+.header { background: #f6f8fa; padding: 0.5em 0.8em; }
+</style>
+</head>
+<body>
+`
+
+const htmlFooter = `</body>
+</html>
+`
+
+// WriteMetricsHTML writes metrics to the given writer as a standalone
+// HTML page, one table per section, e.g. for a lightweight dashboard.
+//
+// The metrics are written even if some subsystems could not be read,
+// in which case the collection error from ReadMetrics is returned.
+func WriteMetricsHTML(w io.Writer, opts ...Option) error {
+	return WriteMetricsAs(w, FormatHTML, opts...)
+}
+
+func writeHTML(w io.Writer, m Metrics, failed map[string]bool, o *options) error {
+	title := "System metrics"
+	if m.Hostname != "" {
+		title += " for " + m.Hostname
+	}
+
+	ew := &errWriter{w: w}
+	fmt.Fprintf(ew, htmlHeader, html.EscapeString(title))
+	fmt.Fprintf(ew, "<h1>%s</h1>\n", html.EscapeString(title))
+	if len(o.header) > 0 {
+		fmt.Fprintf(ew, "<pre class=\"header\">%s</pre>\n", html.EscapeString(strings.Join(o.header, "\n")))
+	}
+	fmt.Fprintf(ew, "<p class=\"generated\">Collected at %s</p>\n", m.CollectedAt.Format(time.RFC1123))
+
+	eachTable(m, failed, o, func(s section, t *titledTable) {
+		fmt.Fprintf(ew, "<h2>%s</h2>\n", html.EscapeString(t.title))
+		fmt.Fprintln(ew, t.RenderHTML())
+	})
+
+	fmt.Fprint(ew, htmlFooter)
+	return ew.err
+}
@@ -0,0 +1,142 @@
+package gonet
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WriteInfluxLineProtocol writes metrics to the given writer in the InfluxDB
+// line protocol, e.g. to pipe them into InfluxDB or Telegraf. Every line
+// carries the collection time, in nanoseconds, and the tags, along with a
+// host tag set to the hostname unless tags has one, e.g.
+//
+//	mem,environment=prod,host=web01 total=16777216000i,used_percent=41.2 1700000000000000000
+//
+// The measurements are cpu, mem, disk (tagged with path and fstype), net
+// (tagged with interface) and system. Measurements of subsystems that
+// could not be read are left out.
+//
+// FormatInflux writes the same lines with only the host tag.
+//
+// The metrics are written even if some subsystems could not be read,
+// in which case the collection error from ReadMetrics is returned.
+func WriteInfluxLineProtocol(w io.Writer, tags map[string]string, opts ...Option) error {
+	metrics, err := ReadMetrics(FormatInflux.readOptions(opts)...)
+	if werr := writeInflux(w, metrics, failedSubsystems(err), tags); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// influxField is a field of a line, with its value formatted.
+type influxField struct {
+	key, value string
+}
+
+func influxFloat(key string, v float64) influxField {
+	return influxField{key, strconv.FormatFloat(v, 'f', -1, 64)}
+}
+
+func influxInt(key string, v uint64) influxField {
+	return influxField{key, strconv.FormatUint(v, 10) + "i"}
+}
+
+func writeInflux(w io.Writer, m Metrics, failed map[string]bool, tags map[string]string) error {
+	base := make(map[string]string, len(tags)+1)
+	if m.Hostname != "" {
+		base["host"] = m.Hostname
+	}
+	for k, v := range tags {
+		base[k] = v
+	}
+
+	bw := bufio.NewWriter(w)
+	timestamp := strconv.FormatInt(m.CollectedAt.UnixNano(), 10)
+	line := func(measurement string, extra map[string]string, fields ...influxField) {
+		bw.WriteString(influxMeasurement.Replace(measurement))
+
+		merged := base
+		if len(extra) > 0 {
+			merged = make(map[string]string, len(base)+len(extra))
+			for k, v := range base {
+				merged[k] = v
+			}
+			for k, v := range extra {
+				merged[k] = v
+			}
+		}
+
+		// tags are sorted by key, as recommended by InfluxDB, and empty ones are invalid
+		keys := make([]string, 0, len(merged))
+		for k, v := range merged {
+			if k != "" && v != "" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			bw.WriteString("," + influxTag.Replace(k) + "=" + influxTag.Replace(merged[k]))
+		}
+
+		for i, f := range fields {
+			sep := ","
+			if i == 0 {
+				sep = " "
+			}
+			bw.WriteString(sep + influxTag.Replace(f.key) + "=" + f.value)
+		}
+		bw.WriteString(" " + timestamp + "\n")
+	}
+
+	if !failed[SubsystemCPUPercent] {
+		line("cpu", nil, influxFloat("usage_percent", m.CPUPercent))
+	}
+
+	if !failed[SubsystemMemory] {
+		line("mem", nil,
+			influxInt("total", m.TotalMemory), influxInt("used", m.UsedMemory),
+			influxInt("free", m.FreeMemory), influxInt("cached", m.CacheMemory),
+			influxFloat("used_percent", m.MemoryUsedPercent))
+	}
+
+	if !failed[SubsystemPartitions] {
+		for _, d := range m.Disks {
+			line("disk", map[string]string{"path": d.Mountpoint, "fstype": d.Fstype},
+				influxInt("total", d.Total), influxInt("used", d.Used), influxInt("free", d.Free),
+				influxFloat("used_percent", d.UsedPercent))
+		}
+	}
+
+	if !failed[SubsystemNetIO] {
+		for _, iface := range sortedKeys(m.NetIO) {
+			c := m.NetIO[iface]
+			line("net", map[string]string{"interface": iface},
+				influxInt("bytes_sent", c.BytesSent), influxInt("bytes_recv", c.BytesRecv),
+				influxInt("packets_sent", c.PacketsSent), influxInt("packets_recv", c.PacketsRecv),
+				influxInt("err_in", c.Errin), influxInt("err_out", c.Errout),
+				influxInt("drop_in", c.Dropin), influxInt("drop_out", c.Dropout))
+		}
+	}
+
+	if !failed[SubsystemHost] {
+		fields := []influxField{
+			influxInt("uptime", uint64(m.Uptime.Seconds())), influxInt("n_processes", m.RunningProcesses),
+		}
+		if !failed[SubsystemLoad] {
+			fields = append(fields,
+				influxFloat("load1", m.LoadAvg.Load1), influxFloat("load5", m.LoadAvg.Load5),
+				influxFloat("load15", m.LoadAvg.Load15))
+		}
+		line("system", nil, fields...)
+	}
+	return bw.Flush()
+}
+
+// Escapes of measurement names, and of tag keys and values and field keys.
+var (
+	influxMeasurement = strings.NewReplacer(",", `\,`, " ", `\ `)
+	influxTag         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
@@ -0,0 +1,58 @@
+package gonet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// WriteMetricsJSON writes metrics to the given writer as indented JSON.
+// Byte counts are written as raw numbers.
+//
+// The metrics are written even if some subsystems could not be read,
+// in which case the collection error from ReadMetrics is returned.
+func WriteMetricsJSON(w io.Writer, opts ...Option) error {
+	return WriteMetricsAs(w, FormatJSON, opts...)
+}
+
+func writeJSON(w io.Writer, m Metrics) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// StreamJSONL writes a metrics snapshot to the given writer every interval
+// as newline-delimited JSON, one compact object per line, until ctx is done
+// or a write fails. Each object carries its collected_at timestamp.
+//
+// Snapshots are written even if some subsystems could not be read.
+// StreamJSONL returns the write error, or the error of ctx once it is done.
+// An interval that is not positive is an error.
+func StreamJSONL(ctx context.Context, w io.Writer, interval time.Duration, opts ...Option) error {
+	if interval <= 0 {
+		return fmt.Errorf("gonet: stream interval must be positive, got %s", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	enc := json.NewEncoder(w)
+	for {
+		metrics, _ := ReadMetricsContext(ctx, opts...)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := enc.Encode(metrics); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package gonet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// WriteMetricsKV writes metrics to the given writer as key=value lines,
+// e.g. cpu_percent=12.34, for shell scripts to pick with grep and cut.
+// The keys are the JSON field names, joined with dots for nested fields
+// and indexes, e.g. disks.0.mountpoint=/ or net_io.eth0.bytes_sent=1024.
+// Lines are sorted by key at each level. Strings that would not fit on
+// a line are quoted as Go string literals, and empty values are left out.
+//
+// The metrics are written even if some subsystems could not be read,
+// in which case the collection error from ReadMetrics is returned and
+// the errors are written as errors.<subsystem>=<message>.
+func WriteMetricsKV(w io.Writer, opts ...Option) error {
+	return WriteMetricsAs(w, FormatKV, opts...)
+}
+
+func writeKV(w io.Writer, m Metrics) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	writeKVValue(bw, "", v)
+	return bw.Flush()
+}
+
+// writeKVValue writes v, decoded from JSON, as lines of keys under prefix.
+func writeKVValue(w *bufio.Writer, prefix string, v interface{}) {
+	key := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			writeKVValue(w, key(k), v[k])
+		}
+	case []interface{}:
+		for i, e := range v {
+			writeKVValue(w, key(strconv.Itoa(i)), e)
+		}
+	case nil:
+	case string:
+		if v == "" {
+			return
+		}
+		if q := strconv.Quote(v); q[1:len(q)-1] != v {
+			v = q
+		}
+		w.WriteString(prefix + "=" + v + "\n")
+	case json.Number:
+		w.WriteString(prefix + "=" + v.String() + "\n")
+	case bool:
+		w.WriteString(prefix + "=" + strconv.FormatBool(v) + "\n")
+	}
+}
@@ -0,0 +1,19 @@
+//go:build linux
+
+package gonet
+
+import (
+	"path/filepath"
+	"strconv"
+)
+
+// getLinkSpeed returns the negotiated speed of the interface in Mb/s,
+// or 0 if it is unknown, e.g. for virtual interfaces and links that
+// are down, which report -1 or fail to read.
+func getLinkSpeed(iface string) int {
+	speed, err := strconv.Atoi(readSysString(filepath.Join("/sys/class/net", iface), "speed"))
+	if err != nil || speed <= 0 {
+		return 0
+	}
+	return speed
+}
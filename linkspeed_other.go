@@ -0,0 +1,8 @@
+//go:build !linux
+
+package gonet
+
+// getLinkSpeed is only implemented on linux.
+func getLinkSpeed(iface string) int {
+	return 0
+}
@@ -0,0 +1,76 @@
+package gonet
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/table"
+)
+
+// Locale translates the tables, e.g. for reports in another language.
+// Tables are in English by default.
+type Locale struct {
+	// Messages maps the English titles, column headers, labels and units
+	// of the tables, e.g. "Disk Usage", "Free Memory" or "GiB", to their
+	// translations. Text without a translation is left in English.
+	Messages map[string]string
+
+	// DecimalSeparator replaces the decimal point of the numbers followed
+	// by a unit or percent sign, e.g. "," for 1,50 GiB. It defaults to ".".
+	DecimalSeparator string
+}
+
+// quantity matches a number followed by a unit or percent sign,
+// e.g. 1.50 GiB, 12.3% or +2 MiB/s.
+var quantity = regexp.MustCompile(`^([+-]?\d+)(\.\d+)?(%| \S+)$`)
+
+// text returns the translation of s. Quantities have their units
+// translated, each part of a compound unit such as MiB/s separately,
+// and their decimal separator replaced.
+func (l *Locale) text(s string) string {
+	if t, ok := l.Messages[s]; ok {
+		return t
+	}
+
+	q := quantity.FindStringSubmatch(s)
+	if q == nil {
+		return s
+	}
+
+	number, fraction, unit := q[1], q[2], q[3]
+	if fraction != "" && l.DecimalSeparator != "" {
+		fraction = l.DecimalSeparator + fraction[1:]
+	}
+
+	if unit != "%" {
+		parts := strings.Split(unit[1:], "/")
+		for i, p := range parts {
+			if t, ok := l.Messages[p]; ok {
+				parts[i] = t
+			}
+		}
+		unit = " " + strings.Join(parts, "/")
+	}
+	return number + fraction + unit
+}
+
+// row returns a copy of r with its text translated.
+func (l *Locale) row(r table.Row) table.Row {
+	translated := make(table.Row, len(r))
+	for i, cell := range r {
+		if s, ok := cell.(string); ok {
+			cell = l.text(s)
+		}
+		translated[i] = cell
+	}
+	return translated
+}
+
+// number returns s with the decimal separator of l replaced by a point,
+// e.g. to parse a translated cell.
+func (l *Locale) number(s string) string {
+	if l == nil || l.DecimalSeparator == "" {
+		return s
+	}
+	return strings.Replace(s, l.DecimalSeparator, ".", 1)
+}
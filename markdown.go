@@ -0,0 +1,30 @@
+package gonet
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMetricsMarkdown writes metrics to the given writer as
+// GitHub-flavored Markdown, one table per section, e.g. for pasting
+// into pull requests and wikis.
+//
+// The metrics are written even if some subsystems could not be read,
+// in which case the collection error from ReadMetrics is returned.
+func WriteMetricsMarkdown(w io.Writer, opts ...Option) error {
+	return WriteMetricsAs(w, FormatMarkdown, opts...)
+}
+
+func writeMarkdown(w io.Writer, m Metrics, failed map[string]bool, o *options) error {
+	ew := &errWriter{w: w}
+	if len(o.header) > 0 {
+		fmt.Fprintf(ew, "```\n%s\n```\n\n", strings.Join(o.header, "\n"))
+	}
+
+	eachTable(m, failed, o, func(s section, t *titledTable) {
+		fmt.Fprintln(ew, t.RenderMarkdown())
+		fmt.Fprintln(ew)
+	})
+	return ew.err
+}
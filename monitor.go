@@ -0,0 +1,241 @@
+package gonet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+)
+
+// sparkLevels are the bars of a sparkline, from 0% to 100%.
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// Monitor keeps the most recent cpu and memory usage samples to show their
+// trend as sparklines, e.g. in a terminal dashboard. Samples are added by
+// Sample, or by reading the metrics on an interval between Start and Stop,
+// which also calls the callbacks registered with OnSample and
+// OnThresholdBreach. It is safe for concurrent use.
+type Monitor struct {
+	mu     sync.Mutex
+	size   int
+	cpu    []float64 // ring buffers of size samples,
+	memory []float64 // the oldest at next once full
+	next   int
+
+	onSample []func(Metrics)
+	onBreach []func(Breach)
+
+	// cancel stops the poller started by Start, closing done once it returns
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewMonitor returns a Monitor keeping the last size samples.
+func NewMonitor(size int) *Monitor {
+	if size < 1 {
+		size = 1
+	}
+	return &Monitor{size: size}
+}
+
+// Sample reads the cpu and memory usage and adds them to the samples,
+// dropping the oldest once there are size of them. The cpu usage is the
+// usage since the previous call, so samples are best taken at a steady pace.
+func (mon *Monitor) Sample() error {
+	cpuPercent, err := CPUPercent(context.Background(), 0)
+	if err != nil {
+		return err
+	}
+
+	memPercent, err := MemoryUsedPercent()
+	if err != nil {
+		return err
+	}
+
+	mon.add(cpuPercent, memPercent)
+	return nil
+}
+
+// OnSample registers fn to be called with the metrics of every poll
+// between Start and Stop.
+func (mon *Monitor) OnSample(fn func(Metrics)) {
+	mon.mu.Lock()
+	defer mon.mu.Unlock()
+	mon.onSample = append(mon.onSample, fn)
+}
+
+// OnThresholdBreach registers fn to be called with every metric above its
+// limit or warning limit, set with WithThresholds, on every poll between
+// Start and Stop. A metric that stays above its limit is reported on
+// every poll.
+func (mon *Monitor) OnThresholdBreach(fn func(Breach)) {
+	mon.mu.Lock()
+	defer mon.mu.Unlock()
+	mon.onBreach = append(mon.onBreach, fn)
+}
+
+// Start reads the metrics every interval, as configured by opts, until ctx
+// is done or Stop is called, adding their cpu and memory usage to the
+// samples. The callbacks are called in the order they were registered, on
+// the polling goroutine: they must be fast, as they delay the next poll,
+// and must not call Stop. Start returns an error if interval is not
+// positive, or if the Monitor was started and not stopped since.
+func (mon *Monitor) Start(ctx context.Context, interval time.Duration, opts ...Option) error {
+	if interval <= 0 {
+		return fmt.Errorf("gonet: monitor interval must be positive, got %s", interval)
+	}
+
+	mon.mu.Lock()
+	defer mon.mu.Unlock()
+
+	if mon.cancel != nil {
+		return errors.New("gonet: monitor already started")
+	}
+
+	ctx, mon.cancel = context.WithCancel(ctx)
+	mon.done = make(chan struct{})
+	go mon.poll(ctx, interval, opts, mon.done)
+	return nil
+}
+
+// Stop stops the polling started by Start and waits for it to return.
+// It does nothing if the Monitor was not started.
+func (mon *Monitor) Stop() {
+	mon.mu.Lock()
+	cancel, done := mon.cancel, mon.done
+	mon.cancel, mon.done = nil, nil
+	mon.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// poll reads the metrics every interval until ctx is done, then closes done.
+func (mon *Monitor) poll(ctx context.Context, interval time.Duration, opts []Option, done chan struct{}) {
+	defer close(done)
+
+	o := newOptions(opts)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m, err := ReadMetricsContext(ctx, opts...)
+		if ctx.Err() != nil {
+			return
+		}
+
+		failed := failedSubsystems(err)
+		if !failed[SubsystemCPUPercent] && !failed[SubsystemMemory] {
+			mon.add(m.CPUPercent, m.MemoryUsedPercent)
+		}
+
+		// registering a callback only appends, so the slices can be used unlocked
+		mon.mu.Lock()
+		onSample, onBreach := mon.onSample, mon.onBreach
+		mon.mu.Unlock()
+
+		for _, fn := range onSample {
+			fn(m)
+		}
+		if len(onBreach) > 0 {
+			for _, b := range m.CheckThresholds(o.thresholds) {
+				for _, fn := range onBreach {
+					fn(b)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// add adds a cpu and memory usage sample.
+func (mon *Monitor) add(cpuPercent, memPercent float64) {
+	mon.mu.Lock()
+	defer mon.mu.Unlock()
+
+	if len(mon.cpu) < mon.size {
+		mon.cpu = append(mon.cpu, cpuPercent)
+		mon.memory = append(mon.memory, memPercent)
+		return
+	}
+
+	mon.cpu[mon.next] = cpuPercent
+	mon.memory[mon.next] = memPercent
+	mon.next = (mon.next + 1) % mon.size
+}
+
+// Samples returns the cpu and memory usage samples, oldest first.
+func (mon *Monitor) Samples() (cpu, memory []float64) {
+	mon.mu.Lock()
+	defer mon.mu.Unlock()
+	return mon.ordered(mon.cpu), mon.ordered(mon.memory)
+}
+
+// ordered returns a copy of the ring buffer samples, oldest first.
+func (mon *Monitor) ordered(samples []float64) []float64 {
+	ordered := make([]float64, 0, len(samples))
+	ordered = append(ordered, samples[mon.next:]...)
+	return append(ordered, samples[:mon.next]...)
+}
+
+// RenderSparklines writes the trend of the cpu and memory usage samples
+// to the given writer as a table of sparklines, e.g. ▁▂▃▅▇, along with
+// the last sample. If writer is nil, it will write to stdout
+// RenderSparklines returns the first write error.
+func (mon *Monitor) RenderSparklines(writer io.Writer, opts ...Option) error {
+	if writer == nil {
+		writer = os.Stdout
+	}
+
+	cpu, memory := mon.Samples()
+	o := newOptions(opts)
+
+	t := newTable("Trend", "Metric", "Trend", "Last")
+	t.AppendRows([]table.Row{
+		{"CPU", sparkline(cpu), lastPercent(cpu)},
+		{"Memory", sparkline(memory), lastPercent(memory)},
+	})
+	t.localize(o.locale)
+	t.SetStyle(o.tableStyle(writer, table.StyleColoredBright))
+	t.fit(o.tableWidth(writer))
+
+	_, err := fmt.Fprintln(writer, t.Render())
+	return err
+}
+
+// sparkline renders percentages from 0 to 100 as a line of bars.
+func sparkline(percents []float64) string {
+	var b strings.Builder
+	for _, p := range percents {
+		level := int(p / 100 * float64(len(sparkLevels)))
+		switch {
+		case level < 0:
+			level = 0
+		case level >= len(sparkLevels):
+			level = len(sparkLevels) - 1
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
+
+// lastPercent formats the last of percents, or "-" if there are none.
+func lastPercent(percents []float64) string {
+	if len(percents) == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", percents[len(percents)-1])
+}
@@ -0,0 +1,23 @@
+package gonet
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMonitorStartInterval(t *testing.T) {
+	mon := NewMonitor(1)
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if err := mon.Start(context.Background(), interval); err == nil {
+			mon.Stop()
+			t.Errorf("Start(%s) returned no error", interval)
+		}
+	}
+
+	// a rejected interval leaves the Monitor stopped
+	if err := mon.Start(context.Background(), time.Hour, WithCPUInterval(time.Millisecond)); err != nil {
+		t.Fatalf("Start(1h) = %v", err)
+	}
+	mon.Stop()
+}
@@ -0,0 +1,215 @@
+package gonet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// NetIOCounters holds the cumulative I/O counters of a network interface.
+// The rates are only set when sampled with WithNetRateInterval.
+type NetIOCounters struct {
+	BytesSent   uint64 `json:"bytes_sent" yaml:"bytes_sent"`
+	BytesRecv   uint64 `json:"bytes_recv" yaml:"bytes_recv"`
+	PacketsSent uint64 `json:"packets_sent" yaml:"packets_sent"`
+	PacketsRecv uint64 `json:"packets_recv" yaml:"packets_recv"`
+	Errin       uint64 `json:"errin" yaml:"errin"`
+	Errout      uint64 `json:"errout" yaml:"errout"`
+	Dropin      uint64 `json:"dropin" yaml:"dropin"`
+	Dropout     uint64 `json:"dropout" yaml:"dropout"`
+
+	// Bytes per second over the sampling interval
+	BytesSentRate float64 `json:"bytes_sent_rate" yaml:"bytes_sent_rate"`
+	BytesRecvRate float64 `json:"bytes_recv_rate" yaml:"bytes_recv_rate"`
+}
+
+// InterfaceFilter selects network interfaces to leave out of the metrics.
+// Filters can be combined, e.g. SkipLoopback|SkipDown.
+type InterfaceFilter int
+
+const (
+	// SkipLoopback leaves out loopback interfaces such as lo.
+	SkipLoopback InterfaceFilter = 1 << iota
+
+	// SkipDown leaves out interfaces that are not up.
+	SkipDown
+)
+
+// skip reports whether the filter leaves out iface.
+func (f InterfaceFilter) skip(iface net.InterfaceStat) bool {
+	var up, loopback bool
+	for _, flag := range iface.Flags {
+		switch flag {
+		case "up":
+			up = true
+		case "loopback":
+			loopback = true
+		}
+	}
+	return (f&SkipLoopback != 0 && loopback) || (f&SkipDown != 0 && !up)
+}
+
+// getNetIO returns the I/O counters of every network interface keyed by name,
+// leaving out the interfaces skipped by filter.
+// If interval is non-zero, the counters are sampled twice, interval apart,
+// to compute the byte rates.
+func getNetIO(ctx context.Context, interval time.Duration, filter InterfaceFilter) (map[string]NetIOCounters, error) {
+	skipped := make(map[string]bool)
+	if filter != 0 {
+		ifaces, err := withContext(ctx, net.InterfacesWithContext)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, iface := range ifaces {
+			skipped[iface.Name] = filter.skip(iface)
+		}
+	}
+
+	first, err := withContext(ctx, func(ctx context.Context) ([]net.IOCountersStat, error) {
+		return net.IOCountersWithContext(ctx, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counters := make(map[string]NetIOCounters, len(first))
+	for _, c := range first {
+		if skipped[c.Name] {
+			continue
+		}
+
+		counters[c.Name] = NetIOCounters{
+			BytesSent:   c.BytesSent,
+			BytesRecv:   c.BytesRecv,
+			PacketsSent: c.PacketsSent,
+			PacketsRecv: c.PacketsRecv,
+			Errin:       c.Errin,
+			Errout:      c.Errout,
+			Dropin:      c.Dropin,
+			Dropout:     c.Dropout,
+		}
+	}
+
+	if interval <= 0 {
+		return counters, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(interval):
+	}
+
+	second, err := withContext(ctx, func(ctx context.Context) ([]net.IOCountersStat, error) {
+		return net.IOCountersWithContext(ctx, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range second {
+		prev, ok := counters[c.Name]
+		if !ok {
+			continue
+		}
+
+		prev.BytesSentRate = perSecond(prev.BytesSent, c.BytesSent, interval)
+		prev.BytesRecvRate = perSecond(prev.BytesRecv, c.BytesRecv, interval)
+		counters[c.Name] = prev
+	}
+	return counters, nil
+}
+
+// perSecond returns the rate of change from before to after over d.
+// Counters that went backwards, e.g. after a reset, give a zero rate.
+func perSecond(before, after uint64, d time.Duration) float64 {
+	if after < before || d <= 0 {
+		return 0
+	}
+	return float64(after-before) / d.Seconds()
+}
+
+// isIPv4 reports whether addr, an address with or without
+// a CIDR prefix length, is an IPv4 address.
+func isIPv4(addr string) bool {
+	ip, ok := parseAddr(addr)
+	return ok && ip.Is4()
+}
+
+// interfaceNames returns the names of the interfaces of m in ascending order.
+// Interfaces without addresses are listed if they have a mac address.
+func (m Metrics) interfaceNames() []string {
+	ifaces := sortedKeys(m.MacAddrs)
+	for _, iface := range sortedKeys(m.IPAddrs) {
+		if _, ok := m.MacAddrs[iface]; !ok {
+			ifaces = append(ifaces, iface)
+		}
+	}
+	sort.Strings(ifaces)
+	return ifaces
+}
+
+// parseAddr parses an address with or without a prefix length.
+func parseAddr(addr string) (netip.Addr, bool) {
+	if prefix, err := netip.ParsePrefix(addr); err == nil {
+		return prefix.Addr(), true
+	}
+
+	ip, err := netip.ParseAddr(addr)
+	return ip, err == nil
+}
+
+// sortAddrs sorts addresses in CIDR notation numerically, IPv4 before IPv6,
+// followed by any that can't be parsed in lexical order.
+func sortAddrs(addrs []string) {
+	sort.SliceStable(addrs, func(i, j int) bool {
+		a, aok := parseAddr(addrs[i])
+		b, bok := parseAddr(addrs[j])
+		switch {
+		case aok && bok:
+			if c := a.Compare(b); c != 0 {
+				return c < 0
+			}
+			return addrs[i] < addrs[j]
+		case aok != bok:
+			return aok
+		default:
+			return addrs[i] < addrs[j]
+		}
+	})
+}
+
+// stripCIDR removes the prefix length from an address in CIDR notation.
+func stripCIDR(addr string) string {
+	if i := strings.IndexByte(addr, '/'); i >= 0 {
+		return addr[:i]
+	}
+	return addr
+}
+
+// getConnections returns the number of TCP connections in each state.
+func getConnections(ctx context.Context) (map[string]int, error) {
+	conns, err := withContext(ctx, func(ctx context.Context) ([]net.ConnectionStat, error) {
+		return net.ConnectionsWithContext(ctx, "tcp")
+	})
+	if errors.Is(err, os.ErrPermission) {
+		return nil, fmt.Errorf("listing tcp connections requires elevated privileges: %w", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, c := range conns {
+		counts[c.Status]++
+	}
+	return counts, nil
+}
@@ -0,0 +1,65 @@
+package gonet
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortAddrs(t *testing.T) {
+	want := []string{
+		"10.0.0.1/24",
+		"10.0.0.1/8",
+		"10.0.0.2/8",
+		"192.168.1.9/24",
+		"192.168.1.10/24",
+		"::1/128",
+		"2001:db8::1/64",
+		"fe80::1/64",
+		"bogus",
+		"fe80::zz",
+	}
+
+	// every rotation of the sorted and reversed addresses sorts the same
+	for _, reversed := range []bool{false, true} {
+		for shift := range want {
+			addrs := make([]string, len(want))
+			for i := range want {
+				j := (i + shift) % len(want)
+				if reversed {
+					j = len(want) - 1 - j
+				}
+				addrs[i] = want[j]
+			}
+
+			in := append([]string(nil), addrs...)
+			sortAddrs(addrs)
+			if !reflect.DeepEqual(addrs, want) {
+				t.Errorf("sortAddrs(%q) = %q, want %q", in, addrs, want)
+			}
+		}
+	}
+}
+
+func TestInterfaceNames(t *testing.T) {
+	want := []string{"docker0", "eth0", "lo", "wlan0"}
+
+	// insert the interfaces in different orders, as map iteration differs
+	for shift := range want {
+		m := Metrics{MacAddrs: make(map[string]string), IPAddrs: make(map[string][]string)}
+		for i := range want {
+			iface := want[(i+shift)%len(want)]
+			if iface != "lo" {
+				m.MacAddrs[iface] = "00:00:00:00:00:00"
+			}
+			if iface != "docker0" {
+				m.IPAddrs[iface] = []string{"127.0.0.1/8"}
+			}
+		}
+
+		for i := 0; i < 10; i++ {
+			if got := m.interfaceNames(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("interfaceNames() = %q, want %q", got, want)
+			}
+		}
+	}
+}
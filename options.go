@@ -0,0 +1,465 @@
+package gonet
+
+import (
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+	"golang.org/x/term"
+)
+
+// Option configures how metrics are read.
+type Option func(*options)
+
+type options struct {
+	diskPath          string
+	pseudoFilesystems bool
+	cpuInterval       time.Duration
+	cpuTimesInterval  time.Duration
+	cpuSmoothing      float64
+	style             *table.Style
+	noColor           bool
+	units             Units
+	fahrenheit        bool
+	precision         int
+	netRateInterval   time.Duration
+	diskIO            bool
+	diskRateInterval  time.Duration
+	stripCIDR         bool
+	interfaceFilter   InterfaceFilter
+	topProcesses      int
+	processFilter     *regexp.Regexp
+	processNetIO      bool
+	connections       bool
+	gpu               bool
+	expandCPUInfo     bool
+	thresholds        Thresholds
+	quiet             bool
+	collector         Collector
+	redaction         Redaction
+	header            []string
+	titles            map[Section]string
+	locale            *Locale
+	width             int
+	barWidth          int
+	raw               *RawMetrics
+	sections          map[Section]bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		diskPath:     defaultDiskPath(),
+		noColor:      os.Getenv("NO_COLOR") != "",
+		topProcesses: defaultTopProcesses,
+		precision:    defaultPrecision,
+		barWidth:     defaultBarWidth,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithDiskPath sets the path of the filesystem whose disk usage is reported.
+// It defaults to "/" (the system drive on windows).
+func WithDiskPath(path string) Option {
+	return func(o *options) {
+		o.diskPath = path
+	}
+}
+
+// WithPseudoFilesystems includes pseudo filesystems such as proc
+// and tmpfs when reporting the usage of all mounted filesystems.
+func WithPseudoFilesystems(include bool) Option {
+	return func(o *options) {
+		o.pseudoFilesystems = include
+	}
+}
+
+// WithCPUInterval sets the duration over which cpu usage is sampled.
+// A non-zero duration blocks ReadMetrics for d and gives an accurate reading.
+// A zero duration (the default) does not block and compares against
+// the previous call, which is meaningless on the first call in a process.
+func WithCPUInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.cpuInterval = d
+	}
+}
+
+// WithCPUTimesInterval samples the cpu times twice, d apart, to report
+// how the cpu time was spent: user, system, idle, iowait and steal.
+// This blocks ReadMetrics for d. A zero duration (the default) reports
+// no breakdown.
+func WithCPUTimesInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.cpuTimesInterval = d
+	}
+}
+
+// WithCPUSmoothing renders an exponential moving average of the cpu usage
+// in WatchMetrics, rather than the usage of each frame, to keep it from
+// jumping between refreshes. factor is the weight of the latest reading,
+// between 0 and 1: the lower it is, the smoother the usage. Other values,
+// including the default 0, turn smoothing off. The metrics themselves
+// keep the raw readings.
+func WithCPUSmoothing(factor float64) Option {
+	return func(o *options) {
+		o.cpuSmoothing = factor
+	}
+}
+
+// WithTableStyle renders every table in the given style,
+// e.g. table.StyleDefault for plain ASCII.
+//
+// Without it, tables written to a terminal are colored and tables
+// written anywhere else use table.StyleLight, without colors.
+func WithTableStyle(style table.Style) Option {
+	return func(o *options) {
+		o.style = &style
+	}
+}
+
+// WithNoColor disables ANSI colors in every table, keeping the borders.
+// Colors are also disabled when the NO_COLOR environment variable is set.
+func WithNoColor() Option {
+	return func(o *options) {
+		o.noColor = true
+	}
+}
+
+// colored reports whether output written to w should be colored.
+func (o *options) colored(w io.Writer) bool {
+	return !o.noColor && (o.style != nil || isTerminal(w))
+}
+
+// tableStyle returns the style for tables written to w,
+// def if w is colored and no style was set.
+func (o *options) tableStyle(w io.Writer, def table.Style) table.Style {
+	if o.style != nil {
+		def = *o.style
+	}
+
+	if o.colored(w) {
+		return def
+	}
+
+	if o.style == nil {
+		return table.StyleLight
+	}
+
+	def.Color = table.ColorOptionsDefault
+	def.Title.Colors = nil
+	return def
+}
+
+// tableWidth returns the width that rows of tables written to w
+// must fit in, or 0 for no limit.
+func (o *options) tableWidth(w io.Writer) int {
+	if o.width > 0 {
+		return o.width
+	}
+
+	if f, ok := w.(*os.File); ok && isTerminal(w) {
+		if width, _, err := term.GetSize(int(f.Fd())); err == nil {
+			return width
+		}
+	}
+	return 0
+}
+
+// isTerminal reports whether w is a character device such as a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// WithWidth wraps the widest columns of tables so that rows fit in width
+// characters. It defaults to the width of the terminal, or no limit for
+// writers that aren't one.
+func WithWidth(width int) Option {
+	return func(o *options) {
+		o.width = width
+	}
+}
+
+// defaultBarWidth is the width of the percent bars of the memory and disk tables.
+const defaultBarWidth = 10
+
+// WithBarWidth sets the width of the percent bars in the memory and disk
+// tables, 10 characters by default. Pass 0 to leave the bars out.
+func WithBarWidth(width int) Option {
+	return func(o *options) {
+		o.barWidth = width
+	}
+}
+
+// withBarHeader appends the header of the percent bars to header,
+// if bars are enabled.
+func (o *options) withBarHeader(header []interface{}) []interface{} {
+	if o.barWidth <= 0 {
+		return header
+	}
+	return append(header, "Usage")
+}
+
+// withBar appends the bar of percent to row, if bars are enabled.
+func (o *options) withBar(row table.Row, percent float64) table.Row {
+	if o.barWidth <= 0 {
+		return row
+	}
+	return append(row, percentBar(percent, o.barWidth))
+}
+
+// WithUnits sets how byte counts are formatted in tables.
+// It defaults to UnitsBinary.
+func WithUnits(units Units) Option {
+	return func(o *options) {
+		o.units = units
+	}
+}
+
+// WithFahrenheit renders temperatures in degrees Fahrenheit rather than
+// Celsius. Temperatures are always read and stored in Celsius.
+func WithFahrenheit(enabled bool) Option {
+	return func(o *options) {
+		o.fahrenheit = enabled
+	}
+}
+
+// WithPrecision sets the number of decimals of byte counts in tables,
+// from 0 to 2. It defaults to 2, e.g. 1.50 GiB; 0 rounds to 2 GiB.
+func WithPrecision(precision int) Option {
+	return func(o *options) {
+		switch {
+		case precision < 0:
+			o.precision = 0
+		case precision > defaultPrecision:
+			o.precision = defaultPrecision
+		default:
+			o.precision = precision
+		}
+	}
+}
+
+// WithNetRateInterval samples the network I/O counters twice, d apart,
+// to report the bytes sent and received per second. This blocks
+// ReadMetrics for d. A zero duration (the default) reports no rates.
+func WithNetRateInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.netRateInterval = d
+	}
+}
+
+// WithDiskIO reports the I/O counters of every block device.
+// It is off by default, as enumerating devices adds latency.
+func WithDiskIO(enabled bool) Option {
+	return func(o *options) {
+		o.diskIO = enabled
+	}
+}
+
+// WithDiskRateInterval samples the disk I/O counters twice, d apart, to
+// report the bytes read and written per second when WithDiskIO is set.
+// This blocks ReadMetrics for d. A zero duration (the default) reports no rates.
+func WithDiskRateInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.diskRateInterval = d
+	}
+}
+
+// WithGPU reports the usage of the NVIDIA GPUs, read with nvidia-smi.
+// It is off by default, as running nvidia-smi adds latency. Machines
+// without nvidia-smi report no GPUs.
+func WithGPU(enabled bool) Option {
+	return func(o *options) {
+		o.gpu = enabled
+	}
+}
+
+// WithStripCIDR shows addresses in tables without their
+// CIDR prefix length, e.g. 192.168.1.2 instead of 192.168.1.2/24.
+func WithStripCIDR() Option {
+	return func(o *options) {
+		o.stripCIDR = true
+	}
+}
+
+// humanReadable formats bytes for display in a table cell.
+func (o *options) humanReadable(bytes uint64) string {
+	return humanReadable(bytes, o.units, o.precision)
+}
+
+// joinAddrs joins addrs for display in a table cell, in sorted order
+// even if the metrics were not read by ReadMetrics.
+func (o *options) joinAddrs(addrs []string) string {
+	sorted := append([]string(nil), addrs...)
+	sortAddrs(sorted)
+	if o.stripCIDR {
+		for i, addr := range sorted {
+			sorted[i] = stripCIDR(addr)
+		}
+	}
+	return strings.Join(sorted, ", ")
+}
+
+// WithInterfaceFilter leaves the network interfaces skipped by
+// filter out of the metrics. All interfaces are reported by default.
+//
+//	gonet.WithInterfaceFilter(gonet.SkipLoopback | gonet.SkipDown)
+func WithInterfaceFilter(filter InterfaceFilter) Option {
+	return func(o *options) {
+		o.interfaceFilter = filter
+	}
+}
+
+// WithTopProcesses sets the number of processes listed in the top
+// processes by cpu and memory, 5 by default. Listing processes reads
+// every process on the system, which can take a while on busy hosts;
+// pass 0 to skip it.
+func WithTopProcesses(n int) Option {
+	return func(o *options) {
+		o.topProcesses = n
+	}
+}
+
+// WithProcessFilter lists every process whose name matches pattern,
+// e.g. regexp.MustCompile("^nginx"), along with their total cpu and memory
+// usage. Use regexp.QuoteMeta to match a plain substring.
+func WithProcessFilter(pattern *regexp.Regexp) Option {
+	return func(o *options) {
+		o.processFilter = pattern
+	}
+}
+
+// WithProcessNetIO reports the network I/O of the processes, to find the
+// ones saturating a link. It is only implemented on linux, which accounts
+// network traffic per network namespace rather than per process: the
+// processes sharing a namespace, e.g. those of the host or of a container,
+// are reported together. Reading the namespaces of other users' processes
+// requires root. It is off by default, as it reads every process.
+func WithProcessNetIO(enabled bool) Option {
+	return func(o *options) {
+		o.processNetIO = enabled
+	}
+}
+
+// WithConnections reports the number of TCP connections in each state.
+// It is off by default, as listing every connection can be slow on busy
+// hosts and may require elevated privileges.
+func WithConnections(enabled bool) Option {
+	return func(o *options) {
+		o.connections = enabled
+	}
+}
+
+// WithExpandedCPUInfo lists every cpu on its own row in the cpu info table.
+// By default identical cpus are collapsed into a single row with their count.
+func WithExpandedCPUInfo(enabled bool) Option {
+	return func(o *options) {
+		o.expandCPUInfo = enabled
+	}
+}
+
+// WithThresholds highlights the cpu row in red when it is above its limit
+// in t, and colors the memory and disk usage cells green below their
+// warning limit, yellow below their limit and red above it, when the
+// tables are colored.
+func WithThresholds(t Thresholds) Option {
+	return func(o *options) {
+		o.thresholds = t
+	}
+}
+
+// WithQuiet renders only the sections with a metric above its limit or
+// warning limit set with WithThresholds, e.g. for a compact alert email.
+// Nothing but the collection time is rendered when no metric is.
+func WithQuiet(enabled bool) Option {
+	return func(o *options) {
+		o.quiet = enabled
+	}
+}
+
+// WithCollector reads the metrics with c rather than from the system,
+// e.g. to test code built on gonet. Options that change how metrics are
+// read only apply to the default Collector, except that c is only asked
+// for the optional subsystems, e.g. SubsystemDiskIO, when their option,
+// e.g. WithDiskIO, is set.
+func WithCollector(c Collector) Option {
+	return func(o *options) {
+		o.collector = c
+	}
+}
+
+// WithRedaction masks the identifying fields selected by r, e.g.
+// RedactMAC|RedactIP|RedactHostname, in the metrics read and in every
+// output format, to make the output safe to share publicly. The gopsutil
+// results of ReadMetricsRaw are not masked.
+func WithRedaction(r Redaction) Option {
+	return func(o *options) {
+		o.redaction = r
+	}
+}
+
+// WithHeader writes lines above the tables of the table, Markdown and HTML
+// output, e.g. the command, user and environment of a capture, to tell
+// archived captures apart.
+func WithHeader(lines ...string) Option {
+	return func(o *options) {
+		o.header = lines
+	}
+}
+
+// WithSections renders only the given sections, in their usual order.
+// All sections are rendered by default.
+//
+//	gonet.WriteMetrics(os.Stdout, gonet.WithSections(gonet.SectionCPU, gonet.SectionMemory))
+func WithSections(sections ...Section) Option {
+	return func(o *options) {
+		o.sections = make(map[Section]bool, len(sections))
+		for _, s := range sections {
+			o.sections[s] = true
+		}
+	}
+}
+
+// WithTitles replaces the titles of the sections' tables, e.g. to translate
+// or brand a report. Sections that render several tables, such as the
+// top processes by cpu and memory, only have their first table retitled.
+//
+//	gonet.WriteMetrics(os.Stdout, gonet.WithTitles(map[gonet.Section]string{
+//		gonet.SectionCPU:  "Prozessor",
+//		gonet.SectionDisk: "Festplatten",
+//	}))
+func WithTitles(titles map[Section]string) Option {
+	return func(o *options) {
+		o.titles = titles
+	}
+}
+
+// WithLocale translates the titles, headers, labels and units of the
+// tables with the messages of l and formats their quantities with its
+// decimal separator. Titles set with WithTitles are not translated.
+//
+//	gonet.WriteMetrics(os.Stdout, gonet.WithLocale(gonet.Locale{
+//		Messages:         map[string]string{"System Memory": "Arbeitsspeicher", "Free Memory": "Frei"},
+//		DecimalSeparator: ",",
+//	}))
+func WithLocale(l Locale) Option {
+	return func(o *options) {
+		o.locale = &l
+	}
+}
+
+// renders reports whether section s is rendered.
+func (o *options) renders(s Section) bool {
+	return o.sections == nil || o.sections[s]
+}
@@ -0,0 +1,28 @@
+package gonet
+
+import (
+	"context"
+	"net"
+)
+
+// primaryIPTargets are public addresses whose route gives the primary IP.
+// Nothing is sent to them: connecting a UDP socket only picks a route.
+var primaryIPTargets = []string{"8.8.8.8:53", "[2001:4860:4860::8888]:53"}
+
+// getPrimaryIP returns the local address used to reach the internet, the
+// source address of the default route, preferring IPv4. It is empty on
+// hosts without a default route.
+func getPrimaryIP(ctx context.Context) string {
+	var d net.Dialer
+	for _, target := range primaryIPTargets {
+		conn, err := d.DialContext(ctx, "udp", target)
+		if err != nil {
+			continue
+		}
+
+		addr := conn.LocalAddr().(*net.UDPAddr)
+		conn.Close()
+		return addr.IP.String()
+	}
+	return ""
+}
@@ -0,0 +1,219 @@
+package gonet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// defaultTopProcesses is the number of processes listed
+// in each of the top processes tables.
+const defaultTopProcesses = 5
+
+// ProcessInfo holds the resource usage of a single process.
+type ProcessInfo struct {
+	PID  int32  `json:"pid" yaml:"pid"`
+	Name string `json:"name" yaml:"name"`
+
+	// CPUPercent is the average cpu usage since the process started.
+	CPUPercent    float64 `json:"cpu_percent" yaml:"cpu_percent"`
+	MemoryPercent float64 `json:"memory_percent" yaml:"memory_percent"`
+	RSS           uint64  `json:"rss" yaml:"rss"`
+}
+
+// getProcesses returns the resource usage of every running process.
+// Processes that exit or can't be read while listing are skipped.
+func getProcesses(ctx context.Context) ([]ProcessInfo, error) {
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]ProcessInfo, 0, len(procs))
+	for _, p := range procs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		name, err := p.NameWithContext(ctx)
+		if err != nil {
+			continue
+		}
+
+		info := ProcessInfo{PID: p.Pid, Name: name}
+		if cpuPercent, err := p.CPUPercentWithContext(ctx); err == nil {
+			info.CPUPercent = cpuPercent
+		}
+
+		if memPercent, err := p.MemoryPercentWithContext(ctx); err == nil {
+			info.MemoryPercent = float64(memPercent)
+		}
+
+		if memInfo, err := p.MemoryInfoWithContext(ctx); err == nil {
+			info.RSS = memInfo.RSS
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// topProcesses returns the n processes with the highest value of key.
+func topProcesses(procs []ProcessInfo, n int, key func(ProcessInfo) float64) []ProcessInfo {
+	sorted := make([]ProcessInfo, len(procs))
+	copy(sorted, procs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return key(sorted[i]) > key(sorted[j])
+	})
+
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// matchProcesses returns the processes whose name matches pattern, by cpu
+// usage, and a ProcessInfo named after pattern holding the sum of their usage.
+func matchProcesses(procs []ProcessInfo, pattern *regexp.Regexp) ([]ProcessInfo, ProcessInfo) {
+	total := ProcessInfo{Name: pattern.String()}
+
+	var matched []ProcessInfo
+	for _, p := range procs {
+		if !pattern.MatchString(p.Name) {
+			continue
+		}
+
+		matched = append(matched, p)
+		total.CPUPercent += p.CPUPercent
+		total.MemoryPercent += p.MemoryPercent
+		total.RSS += p.RSS
+	}
+	return topProcesses(matched, len(matched), func(p ProcessInfo) float64 { return p.CPUPercent }), total
+}
+
+// ProcessNetIO holds the network I/O of the processes sharing a network
+// namespace, as read with WithProcessNetIO. The loopback interface is left
+// out. The rates are only set when sampled with WithNetRateInterval.
+type ProcessNetIO struct {
+	// Namespace, e.g. net:[4026531840], and the pids and names of its processes
+	Namespace string   `json:"namespace" yaml:"namespace"`
+	PIDs      []int32  `json:"pids" yaml:"pids"`
+	Processes []string `json:"processes" yaml:"processes"`
+
+	BytesSent uint64 `json:"bytes_sent" yaml:"bytes_sent"`
+	BytesRecv uint64 `json:"bytes_recv" yaml:"bytes_recv"`
+
+	// Bytes per second over the sampling interval
+	BytesSentRate float64 `json:"bytes_sent_rate" yaml:"bytes_sent_rate"`
+	BytesRecvRate float64 `json:"bytes_recv_rate" yaml:"bytes_recv_rate"`
+}
+
+// ProcessMetrics holds the resource usage of a single process,
+// as read by ReadProcessMetrics.
+type ProcessMetrics struct {
+	ProcessInfo `yaml:",inline"`
+
+	VMS        uint64 `json:"vms" yaml:"vms"`
+	NumThreads int32  `json:"num_threads" yaml:"num_threads"`
+
+	// Open file descriptors, 0 where they can't be counted, e.g. on windows
+	OpenFDs int32 `json:"open_fds" yaml:"open_fds"`
+
+	// When the process started and for how long it has been running
+	CreateTime time.Time     `json:"create_time" yaml:"create_time"`
+	Uptime     time.Duration `json:"uptime" yaml:"uptime"`
+}
+
+// ReadProcessMetrics reads the resource usage of the process with the
+// given pid. It fails if there is no such process or its name and memory
+// can't be read; the other fields are left zero if they can't be read.
+func ReadProcessMetrics(pid int32) (ProcessMetrics, error) {
+	ctx := context.Background()
+	p, err := process.NewProcessWithContext(ctx, pid)
+	if err != nil {
+		return ProcessMetrics{}, err
+	}
+
+	name, err := p.NameWithContext(ctx)
+	if err != nil {
+		return ProcessMetrics{}, err
+	}
+
+	memInfo, err := p.MemoryInfoWithContext(ctx)
+	if err != nil {
+		return ProcessMetrics{}, err
+	}
+
+	pm := ProcessMetrics{
+		ProcessInfo: ProcessInfo{PID: pid, Name: name, RSS: memInfo.RSS},
+		VMS:         memInfo.VMS,
+	}
+
+	if cpuPercent, err := p.CPUPercentWithContext(ctx); err == nil {
+		pm.CPUPercent = cpuPercent
+	}
+
+	if memPercent, err := p.MemoryPercentWithContext(ctx); err == nil {
+		pm.MemoryPercent = float64(memPercent)
+	}
+
+	if threads, err := p.NumThreadsWithContext(ctx); err == nil {
+		pm.NumThreads = threads
+	}
+
+	if fds, err := p.NumFDsWithContext(ctx); err == nil {
+		pm.OpenFDs = fds
+	}
+
+	if created, err := p.CreateTimeWithContext(ctx); err == nil {
+		pm.CreateTime = time.UnixMilli(created)
+		pm.Uptime = time.Since(pm.CreateTime).Truncate(time.Second)
+	}
+	return pm, nil
+}
+
+// WriteProcessMetrics writes the resource usage of the process with
+// the given pid to the given writer as a single table.
+// If writer is nil, it will write to stdout
+// It returns the error of ReadProcessMetrics or of writing.
+func WriteProcessMetrics(writer io.Writer, pid int32, opts ...Option) error {
+	if writer == nil {
+		writer = os.Stdout
+	}
+
+	pm, err := ReadProcessMetrics(pid)
+	if err != nil {
+		return err
+	}
+
+	o := newOptions(opts)
+	t := processMetricsTable(pm, o)
+	t.localize(o.locale)
+	t.SetStyle(o.tableStyle(writer, table.StyleColoredBright))
+	t.fit(o.tableWidth(writer))
+	_, err = fmt.Fprintln(writer, t.Render())
+	return err
+}
+
+// processMetricsTable returns the usage of a single process as a table.
+func processMetricsTable(pm ProcessMetrics, o *options) *titledTable {
+	t := newTable(fmt.Sprintf("Process %d", pm.PID), "Property", "Value")
+	t.AppendRows([]table.Row{
+		{"Name", pm.Name},
+		{"CPU %", fmt.Sprintf("%.2f%%", pm.CPUPercent)},
+		{"Memory %", fmt.Sprintf("%.2f%%", pm.MemoryPercent)},
+		{"RSS", o.humanReadable(pm.RSS)},
+		{"VMS", o.humanReadable(pm.VMS)},
+		{"Threads", pm.NumThreads},
+		{"Open File Descriptors", pm.OpenFDs},
+		{"Started", pm.CreateTime.Format(time.RFC1123)},
+		{"Uptime", formatDuration(pm.Uptime)},
+	})
+	return t
+}
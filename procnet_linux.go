@@ -0,0 +1,118 @@
+//go:build linux
+
+package gonet
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// getProcessNetIO returns the network I/O of every network namespace and
+// the processes in it, by throughput. Linux only accounts network traffic
+// per namespace, so the processes sharing one, e.g. all the processes of a
+// host or of a container, are reported together. Processes whose namespace
+// can't be read, those of other users unless running as root, are skipped.
+// If interval is non-zero, the counters are sampled twice, interval apart,
+// to compute the byte rates.
+func getProcessNetIO(ctx context.Context, interval time.Duration) ([]ProcessNetIO, error) {
+	dirs, err := filepath.Glob("/proc/[0-9]*")
+	if err != nil {
+		return nil, err
+	}
+
+	// pids is the first pid of each namespace, to read its counters from
+	namespaces := make(map[string]*ProcessNetIO)
+	pids := make(map[string]string)
+	for _, dir := range dirs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		ns, err := os.Readlink(filepath.Join(dir, "ns", "net"))
+		if err != nil {
+			continue
+		}
+
+		pid, err := strconv.ParseInt(filepath.Base(dir), 10, 32)
+		if err != nil {
+			continue
+		}
+
+		n, ok := namespaces[ns]
+		if !ok {
+			n = &ProcessNetIO{Namespace: ns}
+			namespaces[ns] = n
+			pids[ns] = dir
+		}
+		n.PIDs = append(n.PIDs, int32(pid))
+		n.Processes = append(n.Processes, readSysString(dir, "comm"))
+	}
+
+	for ns, dir := range pids {
+		namespaces[ns].BytesSent, namespaces[ns].BytesRecv = readNetDev(dir)
+	}
+
+	if interval > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+
+		for ns, dir := range pids {
+			n := namespaces[ns]
+			sent, recv := readNetDev(dir)
+			n.BytesSentRate = perSecond(n.BytesSent, sent, interval)
+			n.BytesRecvRate = perSecond(n.BytesRecv, recv, interval)
+		}
+	}
+
+	usage := make([]ProcessNetIO, 0, len(namespaces))
+	for _, n := range namespaces {
+		usage = append(usage, *n)
+	}
+	sort.Slice(usage, func(i, j int) bool {
+		if interval > 0 {
+			return usage[i].BytesSentRate+usage[i].BytesRecvRate > usage[j].BytesSentRate+usage[j].BytesRecvRate
+		}
+		return usage[i].BytesSent+usage[i].BytesRecv > usage[j].BytesSent+usage[j].BytesRecv
+	})
+	return usage, nil
+}
+
+// readNetDev returns the bytes sent and received by every interface
+// but loopback in the network namespace of the process at dir.
+func readNetDev(dir string) (sent, recv uint64) {
+	f, err := os.Open(filepath.Join(dir, "net", "dev"))
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+
+	// after two header lines, each line is an interface followed by
+	// 8 receive and 8 transmit counters, starting with the bytes
+	scanner := bufio.NewScanner(f)
+	for line := 0; scanner.Scan(); line++ {
+		iface, counters, ok := strings.Cut(scanner.Text(), ":")
+		if line < 2 || !ok || strings.TrimSpace(iface) == "lo" {
+			continue
+		}
+
+		fields := strings.Fields(counters)
+		if len(fields) < 9 {
+			continue
+		}
+
+		r, _ := strconv.ParseUint(fields[0], 10, 64)
+		s, _ := strconv.ParseUint(fields[8], 10, 64)
+		recv += r
+		sent += s
+	}
+	return sent, recv
+}
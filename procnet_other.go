@@ -0,0 +1,13 @@
+//go:build !linux
+
+package gonet
+
+import (
+	"context"
+	"time"
+)
+
+// getProcessNetIO is only implemented on linux.
+func getProcessNetIO(ctx context.Context, interval time.Duration) ([]ProcessNetIO, error) {
+	return nil, nil
+}
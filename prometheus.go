@@ -0,0 +1,203 @@
+package gonet
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// promSample is a single sample of a prometheus metric.
+type promSample struct {
+	labels []string // label name, value pairs
+	value  float64
+}
+
+// promWriter writes metrics in the prometheus text exposition format,
+// keeping the first write error.
+type promWriter struct {
+	w   io.Writer
+	err error
+}
+
+// gauge writes a gauge metric.
+func (p *promWriter) gauge(name, help string, samples ...promSample) {
+	p.metric(name, "gauge", help, samples)
+}
+
+// counter writes a counter metric.
+func (p *promWriter) counter(name, help string, samples ...promSample) {
+	p.metric(name, "counter", help, samples)
+}
+
+// metric writes the HELP and TYPE lines of a metric followed by its samples.
+// Metrics without samples are not written.
+func (p *promWriter) metric(name, typ, help string, samples []promSample) {
+	if p.err != nil || len(samples) == 0 {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(&b, "# TYPE %s %s\n", name, typ)
+	for _, s := range samples {
+		b.WriteString(name)
+		if len(s.labels) > 0 {
+			b.WriteByte('{')
+			for i := 0; i+1 < len(s.labels); i += 2 {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				fmt.Fprintf(&b, "%s=\"%s\"", s.labels[i], promEscape(s.labels[i+1]))
+			}
+			b.WriteByte('}')
+		}
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatFloat(s.value, 'f', -1, 64))
+		b.WriteByte('\n')
+	}
+	_, p.err = io.WriteString(p.w, b.String())
+}
+
+// promEscape escapes a prometheus label value.
+var promEscape = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace
+
+// value returns a sample without labels.
+func value(v float64) promSample {
+	return promSample{value: v}
+}
+
+// WritePrometheus writes metrics to the given writer in the
+// prometheus text exposition format. Metrics of subsystems
+// that could not be read are left out.
+//
+// The metrics are written even if some subsystems could not be read,
+// in which case the collection error from ReadMetrics is returned.
+func WritePrometheus(w io.Writer, opts ...Option) error {
+	return WriteMetricsAs(w, FormatPrometheus, opts...)
+}
+
+func writePrometheus(w io.Writer, m Metrics, failed map[string]bool) error {
+	p := &promWriter{w: w}
+
+	p.gauge("gonet_cpu_count", "Number of logical cpus.", value(float64(m.GoNumCPU)))
+	if !failed[SubsystemCPUPercent] {
+		p.gauge("gonet_cpu_usage_percent", "Total cpu usage in percent.", value(m.CPUPercent))
+	}
+
+	if m.CPUTimes != nil {
+		c := m.CPUTimes
+		p.gauge("gonet_cpu_time_percent", "Share of the cpu time spent in each mode in percent.",
+			promSample{[]string{"mode", "user"}, c.User},
+			promSample{[]string{"mode", "system"}, c.System},
+			promSample{[]string{"mode", "idle"}, c.Idle},
+			promSample{[]string{"mode", "iowait"}, c.IOWait},
+			promSample{[]string{"mode", "steal"}, c.Steal},
+		)
+	}
+
+	if !failed[SubsystemPerCorePercent] {
+		var cores []promSample
+		for core, percent := range m.PerCorePercent {
+			cores = append(cores, promSample{[]string{"core", strconv.Itoa(core)}, percent})
+		}
+		p.gauge("gonet_cpu_core_usage_percent", "Usage of each logical cpu core in percent.", cores...)
+	}
+
+	if !failed[SubsystemMemory] {
+		p.gauge("gonet_memory_total_bytes", "Total system memory in bytes.", value(float64(m.TotalMemory)))
+		p.gauge("gonet_memory_free_bytes", "Free system memory in bytes.", value(float64(m.FreeMemory)))
+		p.gauge("gonet_memory_used_bytes", "Used system memory in bytes.", value(float64(m.UsedMemory)))
+		p.gauge("gonet_memory_cached_bytes", "Cached system memory in bytes.", value(float64(m.CacheMemory)))
+		p.gauge("gonet_memory_used_percent", "Used system memory in percent.", value(m.MemoryUsedPercent))
+	}
+
+	p.gauge("gonet_go_memory_alloc_bytes", "Bytes of heap objects allocated by the Go runtime.", value(float64(m.GoMemory.Alloc)))
+	p.gauge("gonet_go_memory_sys_bytes", "Bytes of memory obtained from the OS by the Go runtime.", value(float64(m.GoMemory.Sys)))
+
+	if !failed[SubsystemFileDescriptors] && m.MaxFDs > 0 {
+		p.gauge("gonet_open_fds", "File descriptors open in the gonet process.", value(float64(m.OpenFDs)))
+		p.gauge("gonet_max_fds", "Soft limit on the file descriptors of the gonet process.", value(float64(m.MaxFDs)))
+	}
+
+	if !failed[SubsystemPartitions] {
+		var total, free, used, inodes, inodesFree []promSample
+		for _, d := range m.Disks {
+			labels := []string{"mountpoint", d.Mountpoint, "fstype", d.Fstype}
+			total = append(total, promSample{labels, float64(d.Total)})
+			free = append(free, promSample{labels, float64(d.Free)})
+			used = append(used, promSample{labels, float64(d.Used)})
+			if d.InodesTotal > 0 {
+				inodes = append(inodes, promSample{labels, float64(d.InodesTotal)})
+				inodesFree = append(inodesFree, promSample{labels, float64(d.InodesFree)})
+			}
+		}
+		p.gauge("gonet_disk_total_bytes", "Size of the filesystem in bytes.", total...)
+		p.gauge("gonet_disk_free_bytes", "Free space on the filesystem in bytes.", free...)
+		p.gauge("gonet_disk_used_bytes", "Used space on the filesystem in bytes.", used...)
+		p.gauge("gonet_disk_inodes", "Number of inodes of the filesystem.", inodes...)
+		p.gauge("gonet_disk_inodes_free", "Number of free inodes of the filesystem.", inodesFree...)
+	}
+
+	if !failed[SubsystemGPU] {
+		var utilization, used, total, temperature []promSample
+		for _, g := range m.GPUs {
+			labels := []string{"gpu", strconv.Itoa(g.Index), "name", g.Name}
+			utilization = append(utilization, promSample{labels, g.UtilizationPercent})
+			used = append(used, promSample{labels, float64(g.MemoryUsed)})
+			total = append(total, promSample{labels, float64(g.MemoryTotal)})
+			temperature = append(temperature, promSample{labels, g.Temperature})
+		}
+		p.gauge("gonet_gpu_utilization_percent", "Utilization of the GPU in percent.", utilization...)
+		p.gauge("gonet_gpu_memory_used_bytes", "Used memory of the GPU in bytes.", used...)
+		p.gauge("gonet_gpu_memory_total_bytes", "Total memory of the GPU in bytes.", total...)
+		p.gauge("gonet_gpu_temperature_celsius", "Temperature of the GPU in degrees Celsius.", temperature...)
+	}
+
+	if !failed[SubsystemHost] {
+		p.gauge("gonet_running_processes", "Number of running processes.", value(float64(m.RunningProcesses)))
+		p.gauge("gonet_uptime_seconds", "Time since the last boot in seconds.", value(m.Uptime.Seconds()))
+		p.gauge("gonet_boot_time_seconds", "Time of the last boot in seconds since the epoch.", value(float64(m.BootTime.Unix())))
+	}
+
+	if !failed[SubsystemLoad] {
+		p.gauge("gonet_load1", "1 minute load average.", value(m.LoadAvg.Load1))
+		p.gauge("gonet_load5", "5 minute load average.", value(m.LoadAvg.Load5))
+		p.gauge("gonet_load15", "15 minute load average.", value(m.LoadAvg.Load15))
+	}
+
+	if !failed[SubsystemNetwork] {
+		var addrs []promSample
+		for _, iface := range sortedKeys(m.IPAddrs) {
+			addrs = append(addrs, promSample{[]string{"interface", iface}, float64(len(m.IPAddrs[iface]))})
+		}
+		p.gauge("gonet_network_interface_addresses", "Number of addresses assigned to the network interface.", addrs...)
+
+		var mtus, speeds []promSample
+		for _, iface := range sortedKeys(m.MTUs) {
+			mtus = append(mtus, promSample{[]string{"interface", iface}, float64(m.MTUs[iface])})
+		}
+		for _, iface := range sortedKeys(m.LinkSpeeds) {
+			speeds = append(speeds, promSample{[]string{"interface", iface}, float64(m.LinkSpeeds[iface]) * 1e6})
+		}
+		p.gauge("gonet_network_interface_mtu_bytes", "MTU of the network interface in bytes.", mtus...)
+		p.gauge("gonet_network_interface_speed_bits", "Negotiated link speed of the network interface in bits per second.", speeds...)
+	}
+
+	if !failed[SubsystemNetIO] {
+		var sent, recv, packetsSent, packetsRecv []promSample
+		for _, iface := range sortedKeys(m.NetIO) {
+			c := m.NetIO[iface]
+			labels := []string{"interface", iface}
+			sent = append(sent, promSample{labels, float64(c.BytesSent)})
+			recv = append(recv, promSample{labels, float64(c.BytesRecv)})
+			packetsSent = append(packetsSent, promSample{labels, float64(c.PacketsSent)})
+			packetsRecv = append(packetsRecv, promSample{labels, float64(c.PacketsRecv)})
+		}
+		p.counter("gonet_network_sent_bytes_total", "Bytes sent by the network interface.", sent...)
+		p.counter("gonet_network_received_bytes_total", "Bytes received by the network interface.", recv...)
+		p.counter("gonet_network_sent_packets_total", "Packets sent by the network interface.", packetsSent...)
+		p.counter("gonet_network_received_packets_total", "Packets received by the network interface.", packetsRecv...)
+	}
+	return p.err
+}
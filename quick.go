@@ -0,0 +1,23 @@
+package gonet
+
+import (
+	"context"
+	"time"
+)
+
+// CPUPercent returns the total cpu usage over interval without reading
+// any other metric, e.g. for frequent health checks. A zero interval
+// reports the usage since the previous call.
+func CPUPercent(ctx context.Context, interval time.Duration) (float64, error) {
+	var m Metrics
+	err := collectCPUPercent(ctx, &options{cpuInterval: interval}, &m)
+	return m.CPUPercent, err
+}
+
+// MemoryUsedPercent returns the used system memory as a percentage
+// of the total without reading any other metric.
+func MemoryUsedPercent() (float64, error) {
+	var m Metrics
+	err := collectMemory(context.Background(), &options{}, &m)
+	return m.MemoryUsedPercent, err
+}
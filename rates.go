@@ -0,0 +1,105 @@
+package gonet
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+)
+
+// RateMetrics holds metrics sampled twice, Interval apart, by ReadRates.
+// The network and disk I/O counters hold the absolute values of the first
+// sample along with their per-second rates, and CPUPercent and CPUTimes
+// are measured over Interval.
+type RateMetrics struct {
+	Metrics `yaml:",inline"`
+
+	Interval time.Duration `json:"interval" yaml:"interval"`
+}
+
+// ReadRates reads metrics like ReadMetricsContext, sampling the cpu usage
+// and the network and disk I/O counters over interval to report rates.
+// The samples are taken concurrently, so ReadRates blocks for about interval.
+func ReadRates(ctx context.Context, interval time.Duration, opts ...Option) (RateMetrics, error) {
+	// disk I/O can still be turned off, the intervals can't be overridden
+	opts = append([]Option{WithDiskIO(true)}, opts...)
+	opts = append(opts, WithCPUInterval(interval), WithCPUTimesInterval(interval), WithNetRateInterval(interval), WithDiskRateInterval(interval))
+
+	m, err := ReadMetricsContext(ctx, opts...)
+	return RateMetrics{Metrics: m, Interval: interval}, err
+}
+
+// ReadRatesAgainstFile reads metrics like ReadMetrics and reports the network
+// and disk I/O rates since the metrics saved in the file at statePath by the
+// previous call, then replaces the file with the current metrics. This gives
+// approximate rates across separate runs, e.g. from cron, without blocking
+// to sample them. Interval is the time since the previous call.
+//
+// A missing or corrupt state file is treated as the first call, reporting no
+// rates and a zero Interval. Counters that went backwards since, e.g. after
+// a reboot, give a zero rate.
+//
+// The state file is replaced even if some subsystems could not be read.
+// The error of writing it is returned, otherwise the collection error.
+func ReadRatesAgainstFile(statePath string, opts ...Option) (RateMetrics, error) {
+	// disk I/O can still be turned off
+	opts = append([]Option{WithDiskIO(true)}, opts...)
+
+	m, err := ReadMetrics(opts...)
+	rm := RateMetrics{Metrics: m}
+	if prev, ok := readState(statePath); ok && m.CollectedAt.After(prev.CollectedAt) {
+		rm.Interval = m.CollectedAt.Sub(prev.CollectedAt)
+		rm.NetIO, rm.DiskIO = netRates(prev.NetIO, m.NetIO, rm.Interval), diskRates(prev.DiskIO, m.DiskIO, rm.Interval)
+	}
+
+	if werr := writeFileAtomic(statePath, func(w io.Writer) error {
+		return writeJSON(w, rm.Metrics)
+	}); werr != nil {
+		return rm, werr
+	}
+	return rm, err
+}
+
+// readState returns the metrics saved in the state file at path,
+// and false if it is missing or can't be decoded.
+func readState(path string) (Metrics, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Metrics{}, false
+	}
+
+	var m Metrics
+	if err := json.Unmarshal(data, &m); err != nil || m.CollectedAt.IsZero() {
+		return Metrics{}, false
+	}
+	return m, true
+}
+
+// netRates returns a copy of counters with the rates since prev, d before.
+func netRates(prev, counters map[string]NetIOCounters, d time.Duration) map[string]NetIOCounters {
+	rated := make(map[string]NetIOCounters, len(counters))
+	for name, c := range counters {
+		if p, ok := prev[name]; ok {
+			c.BytesSentRate = perSecond(p.BytesSent, c.BytesSent, d)
+			c.BytesRecvRate = perSecond(p.BytesRecv, c.BytesRecv, d)
+		}
+		rated[name] = c
+	}
+	return rated
+}
+
+// diskRates returns a copy of counters with the rates since prev, d before.
+func diskRates(prev, counters map[string]DiskIOCounters, d time.Duration) map[string]DiskIOCounters {
+	rated := make(map[string]DiskIOCounters, len(counters))
+	for name, c := range counters {
+		if p, ok := prev[name]; ok {
+			c.ReadBytesRate = perSecond(p.ReadBytes, c.ReadBytes, d)
+			c.WriteBytesRate = perSecond(p.WriteBytes, c.WriteBytes, d)
+			c.ReadCountRate = perSecond(p.ReadCount, c.ReadCount, d)
+			c.WriteCountRate = perSecond(p.WriteCount, c.WriteCount, d)
+		}
+		rated[name] = c
+	}
+	return rated
+}
@@ -0,0 +1,41 @@
+package gonet
+
+import (
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
+)
+
+// RawMetrics holds the metrics along with the gopsutil results they were
+// read from, for fields that Metrics doesn't surface. A result is nil if
+// its subsystem could not be read.
+type RawMetrics struct {
+	Metrics `yaml:",inline"`
+
+	RawDiskUsage     *disk.UsageStat        `json:"raw_disk_usage" yaml:"raw_disk_usage"`
+	RawVirtualMemory *mem.VirtualMemoryStat `json:"raw_virtual_memory" yaml:"raw_virtual_memory"`
+	RawCPUs          []cpu.InfoStat         `json:"raw_cpus" yaml:"raw_cpus"`
+	RawHost          *host.InfoStat         `json:"raw_host" yaml:"raw_host"`
+	RawLoad          *load.AvgStat          `json:"raw_load" yaml:"raw_load"`
+	RawInterfaces    []net.InterfaceStat    `json:"raw_interfaces" yaml:"raw_interfaces"`
+}
+
+// ReadMetricsRaw reads metrics like ReadMetrics, also keeping the gopsutil
+// results they were read from.
+func ReadMetricsRaw(opts ...Option) (RawMetrics, error) {
+	var raw RawMetrics
+	m, err := ReadMetrics(append(opts, withRaw(&raw))...)
+	raw.Metrics = m
+	return raw, err
+}
+
+// withRaw keeps the gopsutil results of the system collector in raw.
+// Each subsystem sets only its own result.
+func withRaw(raw *RawMetrics) Option {
+	return func(o *options) {
+		o.raw = raw
+	}
+}
@@ -0,0 +1,44 @@
+package gonet
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/mem"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRawMetricsInline(t *testing.T) {
+	raw := RawMetrics{
+		Metrics:          Metrics{Hostname: "web01", DiskUsage: 42},
+		RawVirtualMemory: &mem.VirtualMemoryStat{Total: 16},
+	}
+
+	for name, marshal := range map[string]func(interface{}) ([]byte, error){
+		"json": json.Marshal,
+		"yaml": yaml.Marshal,
+	} {
+		data, err := marshal(raw)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		var fields map[string]interface{}
+		if err := yaml.Unmarshal(data, &fields); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if _, ok := fields["metrics"]; ok {
+			t.Errorf("%s: metrics are nested under \"metrics\"", name)
+		}
+		if fields["hostname"] != "web01" {
+			t.Errorf("%s: hostname = %v, want web01", name, fields["hostname"])
+		}
+		if fields["disk_usage"] != 42 {
+			t.Errorf("%s: disk_usage = %v, want 42", name, fields["disk_usage"])
+		}
+		if _, ok := fields["raw_virtual_memory"]; !ok {
+			t.Errorf("%s: raw_virtual_memory is missing", name)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package gonet
+
+import "strings"
+
+// Redaction selects identifying fields to mask, e.g. before sharing the
+// output publicly. Redactions can be combined, e.g. RedactMAC|RedactIP.
+type Redaction int
+
+const (
+	// RedactMAC masks hardware addresses as xx:xx:xx:xx:xx:xx.
+	RedactMAC Redaction = 1 << iota
+
+	// RedactIP masks the addresses of the interfaces, the primary IP, the
+	// default gateway and the DNS servers, keeping their CIDR prefix length.
+	RedactIP
+
+	// RedactHostname masks the hostname.
+	RedactHostname
+
+	// RedactSerial masks the serial number of the hardware.
+	RedactSerial
+)
+
+// redacted replaces masked values that have no recognizable shape.
+const redacted = "<redacted>"
+
+// redact masks the fields of m selected by r.
+func (m *Metrics) redact(r Redaction) {
+	if r&RedactMAC != 0 {
+		if m.MacAddr != "" {
+			m.MacAddr = redactMAC(m.MacAddr)
+		}
+
+		macAddrs := make(map[string]string, len(m.MacAddrs))
+		for iface, addr := range m.MacAddrs {
+			macAddrs[iface] = redactMAC(addr)
+		}
+		m.MacAddrs = macAddrs
+	}
+
+	if r&RedactIP != 0 {
+		for _, addrs := range []*map[string][]string{&m.IPAddrs, &m.IPv4Addrs, &m.IPv6Addrs} {
+			redactedAddrs := make(map[string][]string, len(*addrs))
+			for iface, a := range *addrs {
+				for _, addr := range a {
+					redactedAddrs[iface] = append(redactedAddrs[iface], redactIP(addr))
+				}
+			}
+			*addrs = redactedAddrs
+		}
+
+		if m.PrimaryIP != "" {
+			m.PrimaryIP = redactIP(m.PrimaryIP)
+		}
+
+		if m.DefaultGateway != "" {
+			m.DefaultGateway = redactIP(m.DefaultGateway)
+		}
+
+		var dnsServers []string
+		for _, addr := range m.DNSServers {
+			dnsServers = append(dnsServers, redactIP(addr))
+		}
+		m.DNSServers = dnsServers
+	}
+
+	if r&RedactHostname != 0 && m.Hostname != "" {
+		m.Hostname = redacted
+	}
+
+	if r&RedactSerial != 0 && m.Hardware.Serial != "" {
+		m.Hardware.Serial = redacted
+	}
+}
+
+// redactMAC masks every hex digit of a hardware address.
+func redactMAC(addr string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ':' || r == '-' {
+			return r
+		}
+		return 'x'
+	}, addr)
+}
+
+// redactIP masks an address, with or without a CIDR prefix length,
+// keeping its family and prefix length, e.g. x.x.x.x/24.
+func redactIP(addr string) string {
+	ip, prefix, hasPrefix := strings.Cut(addr, "/")
+
+	masked := "x.x.x.x"
+	if strings.Contains(ip, ":") {
+		masked = "x:x:x:x:x:x:x:x"
+	}
+
+	if hasPrefix {
+		return masked + "/" + prefix
+	}
+	return masked
+}
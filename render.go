@@ -0,0 +1,939 @@
+package gonet
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/jedib0t/go-pretty/text"
+)
+
+// Section identifies a group of tables rendered by WriteMetrics.
+type Section int
+
+const (
+	SectionCPU          Section = iota // cpu count and usage
+	SectionCPUCores                    // usage of each logical core
+	SectionCPUInfo                     // vendor, model and speed of each cpu
+	SectionDisk                        // usage of each mounted filesystem
+	SectionMemory                      // system memory
+	SectionPlatform                    // host, platform, load and uptime
+	SectionTemperatures                // sensor temperatures
+	SectionBattery                     // battery status
+	SectionProcesses                   // top processes by cpu and memory
+	SectionNetwork                     // network interfaces and addresses
+	SectionNetIO                       // network I/O counters
+	SectionGoRuntime                   // memory of the Go runtime
+	SectionConnections                 // tcp connections by state
+	SectionDiskIO                      // disk I/O counters
+	SectionProcessNetIO                // network I/O of the processes
+	SectionHardware                    // vendor, product and firmware
+	SectionCPUTimes                    // breakdown of the cpu time
+	SectionGPU                         // usage of the NVIDIA GPUs
+)
+
+// section describes how to build the tables of a Section.
+type section struct {
+	section Section
+	style   table.Style // style used on terminals unless one is set
+	tables  func(m Metrics, failed map[string]bool, o *options) []*titledTable
+}
+
+// sections lists every section in the order they are rendered.
+var sections = []section{
+	{SectionCPU, table.StyleColoredBlackOnBlueWhite, cpuTables},
+	{SectionCPUTimes, table.StyleColoredBright, cpuTimesTables},
+	{SectionCPUCores, table.StyleColoredBright, cpuCoresTables},
+	{SectionCPUInfo, table.StyleColoredBright, cpuInfoTables},
+	{SectionDisk, table.StyleColoredBright, diskTables},
+	{SectionDiskIO, table.StyleColoredBright, diskIOTables},
+	{SectionMemory, table.StyleColoredBright, memoryTables},
+	{SectionGoRuntime, table.StyleColoredBright, goRuntimeTables},
+	{SectionPlatform, table.StyleColoredBright, platformTables},
+	{SectionHardware, table.StyleColoredBright, hardwareTables},
+	{SectionGPU, table.StyleColoredBright, gpuTables},
+	{SectionTemperatures, table.StyleColoredBright, temperatureTables},
+	{SectionBattery, table.StyleColoredBright, batteryTables},
+	{SectionProcesses, table.StyleColoredBright, processTables},
+	{SectionNetwork, table.StyleColoredBright, networkTables},
+	{SectionNetIO, table.StyleColoredBright, netIOTables},
+	{SectionProcessNetIO, table.StyleColoredBright, processNetIOTables},
+	{SectionConnections, table.StyleColoredBright, connectionTables},
+}
+
+// renderMetrics writes metrics as tables to writer, returning the first
+// write error. Sections of failed subsystems are marked unavailable.
+func renderMetrics(writer io.Writer, metrics Metrics, failed map[string]bool, o *options) error {
+	// colors and width are decided by writer, ew only keeps the error
+	ew := &errWriter{w: writer}
+	fmt.Fprintln(ew)
+	for _, line := range o.header {
+		fmt.Fprintln(ew, line)
+	}
+	fmt.Fprintf(ew, "Collected at %s\n\n", metrics.CollectedAt.Format(time.RFC1123))
+	eachTable(metrics, failed, o, func(s section, t *titledTable) {
+		t.SetStyle(o.tableStyle(writer, s.style))
+		t.fit(o.tableWidth(writer))
+		if o.colored(writer) {
+			var configs []table.ColumnConfig
+			if t.bar > 0 {
+				configs = append(configs, table.ColumnConfig{Number: t.bar, Transformer: unicodeBar})
+			}
+			if t.usage > 0 && (t.warning > 0 || t.critical > 0) {
+				configs = append(configs, table.ColumnConfig{Number: t.usage, Transformer: usageColor(t.warning, t.critical, o.locale)})
+			}
+			t.SetColumnConfigs(configs)
+		}
+		if t.highlight != nil && o.colored(writer) {
+			t.SetRowPainter(func(row table.Row) text.Colors {
+				if t.highlight(row) {
+					return text.Colors{text.FgHiRed}
+				}
+				return nil
+			})
+		}
+		fmt.Fprintln(ew, t.Render())
+		fmt.Fprintln(ew)
+	})
+
+	if metrics.GopsutilVersion != "" {
+		fmt.Fprintf(ew, "Read with gopsutil %s\n", metrics.GopsutilVersion)
+	}
+	return ew.err
+}
+
+// eachTable calls fn with every table of the sections selected in o, in order.
+// In quiet mode only the sections above their thresholds are selected.
+func eachTable(metrics Metrics, failed map[string]bool, o *options, fn func(s section, t *titledTable)) {
+	var alerting map[Section]bool
+	if o.quiet {
+		alerting = metrics.alertingSections(o.thresholds)
+	}
+
+	for _, s := range sections {
+		if !o.renders(s.section) || (o.quiet && !alerting[s.section]) {
+			continue
+		}
+
+		for i, t := range s.tables(metrics, failed, o) {
+			t.localize(o.locale)
+			if title, ok := o.titles[s.section]; ok && i == 0 {
+				t.title = title
+				t.SetTitle("%s", title)
+			}
+			fn(s, t)
+		}
+	}
+}
+
+// errWriter is an io.Writer that keeps the first error of the underlying
+// writer and skips every write after it.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+
+	var n int
+	n, e.err = e.w.Write(p)
+	return n, e.err
+}
+
+// percentBar renders p, a percentage from 0 to 100, as a bar of width
+// characters, e.g. [#####-----] for 50%.
+func percentBar(p float64, width int) string {
+	p = math.Max(0, math.Min(p, 100))
+	filled := int(math.Round(p / 100 * float64(width)))
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
+
+// unicodeBar draws the bars of percentBar with block characters, e.g. █████░░░░░.
+func unicodeBar(v interface{}) string {
+	bar, ok := v.(string)
+	if !ok || !strings.HasPrefix(bar, "[") {
+		return fmt.Sprint(v)
+	}
+
+	bar = strings.Trim(bar, "[]")
+	return strings.NewReplacer("#", "█", "-", "░").Replace(bar)
+}
+
+// usageColor colors the percentages of percentCell, translated with l,
+// green below warning, yellow below critical and red above it.
+// A zero limit is not checked.
+func usageColor(warning, critical float64, l *Locale) text.Transformer {
+	return func(v interface{}) string {
+		cell := fmt.Sprint(v)
+		p, err := strconv.ParseFloat(l.number(strings.TrimSuffix(cell, "%")), 64)
+		if err != nil {
+			return cell
+		}
+
+		switch {
+		case critical > 0 && p > critical:
+			return text.FgHiRed.Sprint(cell)
+		case warning > 0 && p > warning:
+			return text.FgHiYellow.Sprint(cell)
+		default:
+			return text.FgHiGreen.Sprint(cell)
+		}
+	}
+}
+
+// percentCell formats a percentage of total for a table cell,
+// N/A if total is 0 and the percentage is meaningless.
+func percentCell(p float64, total uint64) string {
+	if total == 0 {
+		return "N/A"
+	}
+	return fmt.Sprintf("%.1f%%", p)
+}
+
+// titledTable is a table that keeps its title, for output formats
+// that don't render the title of the table themselves.
+type titledTable struct {
+	table.Writer
+	title string
+
+	// highlight reports whether a row is above its threshold, if set
+	highlight func(row table.Row) bool
+
+	// header and rows are kept to be translated by localize
+	header table.Row
+	rows   []table.Row
+
+	// widths are the widths of the longest cell of each column
+	widths []int
+
+	// bar is the number of the column of percent bars, if any
+	bar int
+
+	// usage is the number of the column of usage percentages,
+	// if any, colored by their warning and critical limits
+	usage             int
+	warning, critical float64
+}
+
+// minColumnWidth is the width below which fit doesn't narrow columns.
+const minColumnWidth = 8
+
+// AppendRow appends row, keeping track of the widths of its columns.
+func (t *titledTable) AppendRow(row table.Row) {
+	t.measure(row)
+	t.rows = append(t.rows, row)
+	t.Writer.AppendRow(row)
+}
+
+// AppendRows appends rows, keeping track of the widths of their columns.
+func (t *titledTable) AppendRows(rows []table.Row) {
+	for _, row := range rows {
+		t.AppendRow(row)
+	}
+}
+
+func (t *titledTable) measure(row table.Row) {
+	for i, cell := range row {
+		if i == len(t.widths) {
+			t.widths = append(t.widths, 0)
+		}
+
+		for _, line := range strings.Split(fmt.Sprint(cell), "\n") {
+			if w := text.RuneCount(line); w > t.widths[i] {
+				t.widths[i] = w
+			}
+		}
+	}
+}
+
+// fit wraps the widest columns so that the rows are at most width long,
+// without narrowing any column below minColumnWidth. A zero width is no limit.
+func (t *titledTable) fit(width int) {
+	if width <= 0 {
+		return
+	}
+
+	// every column is padded by a space on each side and followed by a border
+	budget := width - 3*len(t.widths) - 1
+	capped := func(limit int) (total int) {
+		for _, w := range t.widths {
+			if w > limit {
+				w = limit
+			}
+			total += w
+		}
+		return total
+	}
+
+	limit := 0
+	for _, w := range t.widths {
+		if w > limit {
+			limit = w
+		}
+	}
+	if capped(limit) <= budget {
+		return
+	}
+
+	for limit > minColumnWidth && capped(limit) > budget {
+		limit--
+	}
+
+	lengths := make([]int, len(t.widths))
+	for i, w := range t.widths {
+		if w > limit {
+			lengths[i] = limit
+		}
+	}
+	t.SetAllowedColumnLengths(lengths)
+}
+
+// newTable returns a table with the given title and header.
+func newTable(title string, header ...interface{}) *titledTable {
+	t := table.NewWriter()
+	t.SetTitle("%s", title)
+	t.AppendHeader(header)
+
+	tt := &titledTable{Writer: t, title: title, header: header}
+	tt.measure(header)
+	return tt
+}
+
+// localize translates the title, header and rows of the table with l,
+// if not nil. It must be called before the table is styled.
+func (t *titledTable) localize(l *Locale) {
+	if l == nil {
+		return
+	}
+
+	w := table.NewWriter()
+	t.title = l.text(t.title)
+	w.SetTitle("%s", t.title)
+
+	t.widths = nil
+	header := l.row(t.header)
+	t.measure(header)
+	w.AppendHeader(header)
+
+	for _, row := range t.rows {
+		row = l.row(row)
+		t.measure(row)
+		w.AppendRow(row)
+	}
+	t.Writer = w
+}
+
+// cpu metrics and usage
+func cpuTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	cpuUsage := fmt.Sprintf("%.2f%%", m.CPUPercent)
+	if failed[SubsystemCPUPercent] {
+		cpuUsage = m.unavailable(SubsystemCPUPercent)
+	}
+
+	var logical, physical interface{} = m.LogicalCores, m.PhysicalCores
+	if failed[SubsystemCPUCounts] {
+		logical, physical = m.unavailable(SubsystemCPUCounts), m.unavailable(SubsystemCPUCounts)
+	}
+
+	t := newTable("CPU Usage", "CPUs", "Logical Cores", "Physical Cores", "CPU Usage")
+	t.AppendRow(table.Row{m.GoNumCPU, logical, physical, cpuUsage})
+
+	breached := m.breached(o.thresholds)
+	t.highlight = func(table.Row) bool { return breached["cpu"] }
+	return []*titledTable{t}
+}
+
+// breakdown of the cpu time, if sampled
+func cpuTimesTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	t := newTable("CPU Time", "User", "System", "Idle", "IOWait", "Steal")
+	switch {
+	case failed[SubsystemCPUTimes]:
+		t.AppendRow(m.unavailableRow(SubsystemCPUTimes, 5))
+	case m.CPUTimes != nil:
+		c := m.CPUTimes
+		t.AppendRow(table.Row{
+			fmt.Sprintf("%.2f%%", c.User), fmt.Sprintf("%.2f%%", c.System), fmt.Sprintf("%.2f%%", c.Idle),
+			fmt.Sprintf("%.2f%%", c.IOWait), fmt.Sprintf("%.2f%%", c.Steal),
+		})
+	default:
+		return nil
+	}
+	return []*titledTable{t}
+}
+
+// usage of each logical core
+func cpuCoresTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	t := newTable("Core Usage", "Core", "Usage")
+	for core, percent := range m.PerCorePercent {
+		t.AppendRow(table.Row{core, fmt.Sprintf("%.2f%%", percent)})
+	}
+
+	if failed[SubsystemPerCorePercent] {
+		t.AppendRow(m.unavailableRow(SubsystemPerCorePercent, 2))
+	}
+	return []*titledTable{t}
+}
+
+// architecture and stats of the cpus, identical cpus
+// on a single row unless the expanded listing is asked for.
+// The core type is only shown on hybrid cpus, the current
+// and maximum frequencies where they are known.
+func cpuInfoTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	hybrid, scaling := false, false
+	for _, c := range m.CPUInfo {
+		hybrid = hybrid || c.CoreType != ""
+		scaling = scaling || c.CurrentMHz > 0 || c.MaxMHz > 0
+	}
+
+	header := []interface{}{"Vendor ID", "Family", "Cores", "Model", "Speed"}
+	row := func(first interface{}, c CPUInfo, current string) table.Row {
+		r := table.Row{first, c.VendorID, c.Family, c.Cores, c.Model, c.Speed}
+		if scaling {
+			r = append(r, current, formatMHz(c.MaxMHz))
+		}
+		if hybrid {
+			r = append(r, c.CoreType)
+		}
+		return r
+	}
+	if scaling {
+		header = append(header, "Current Speed", "Max Speed")
+	}
+	if hybrid {
+		header = append(header, "Type")
+	}
+
+	var t *titledTable
+	if o.expandCPUInfo {
+		t = newTable("CPU Info", append([]interface{}{"#"}, header...)...)
+		for _, c := range m.CPUInfo {
+			t.AppendRow(row(c.Index, c, formatMHz(c.CurrentMHz)))
+		}
+	} else {
+		t = newTable("CPU Info", append([]interface{}{"Count"}, header...)...)
+		for _, g := range groupCPUInfo(m.CPUInfo) {
+			current := formatMHz(g.minCurrent)
+			if g.maxCurrent != g.minCurrent {
+				current = fmt.Sprintf("%.0f-%.0f MHz", g.minCurrent, g.maxCurrent)
+			}
+			t.AppendRow(row(g.count, g.CPUInfo, current))
+		}
+	}
+
+	if failed[SubsystemCPU] {
+		t.AppendRow(m.unavailableRow(SubsystemCPU, len(header)+1))
+	}
+	return []*titledTable{t}
+}
+
+// formatMHz formats a frequency in MHz, or "-" if unknown.
+func formatMHz(mhz float64) string {
+	if mhz <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f MHz", mhz)
+}
+
+// cpuGroup is a cpu, the number of cpus identical to it
+// and the range of their current frequencies.
+type cpuGroup struct {
+	CPUInfo
+	count                  int
+	minCurrent, maxCurrent float64
+}
+
+// groupCPUInfo groups the cpus that share their vendor, family, cores,
+// model, speed, maximum frequency and core type, in the order they first
+// appear, so that the clusters of hybrid cpus stay apart.
+func groupCPUInfo(cpus []CPUInfo) []cpuGroup {
+	var groups []cpuGroup
+	index := make(map[CPUInfo]int)
+	for _, c := range cpus {
+		current := c.CurrentMHz
+		c.Index, c.CurrentMHz = 0, 0
+		if i, ok := index[c]; ok {
+			g := &groups[i]
+			g.count++
+			if current < g.minCurrent {
+				g.minCurrent = current
+			}
+			if current > g.maxCurrent {
+				g.maxCurrent = current
+			}
+			continue
+		}
+
+		index[c] = len(groups)
+		groups = append(groups, cpuGroup{c, 1, current, current})
+	}
+	return groups
+}
+
+// disk usage for every mounted filesystem
+func diskTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	header := []interface{}{"Mountpoint", "Fstype", "Disk Size", "Disk Free", "Disk Usage", "Disk Usage %"}
+	t := newTable("Disk Usage", o.withBarHeader(header)...)
+	for _, d := range m.Disks {
+		t.AppendRow(o.withBar(table.Row{
+			d.Mountpoint, d.Fstype, o.humanReadable(d.Total), o.humanReadable(d.Free), o.humanReadable(d.Used),
+			percentCell(d.UsedPercent, d.Total),
+		}, d.UsedPercent))
+	}
+	if o.barWidth > 0 {
+		t.bar = len(header) + 1
+	}
+
+	if failed[SubsystemPartitions] {
+		t.AppendRow(m.unavailableRow(SubsystemPartitions, len(o.withBarHeader(header))))
+	}
+
+	t.usage, t.warning, t.critical = len(header), o.thresholds.DiskWarningPercent, o.thresholds.DiskPercent
+	return append([]*titledTable{t}, inodeTables(m)...)
+}
+
+// inode usage of the filesystems that have a fixed number of inodes,
+// which can run out before the space does
+func inodeTables(m Metrics) []*titledTable {
+	t := newTable("Inode Usage", "Mountpoint", "Inodes", "Free", "Used", "Used %")
+	rows := 0
+	for _, d := range m.Disks {
+		if d.InodesTotal == 0 {
+			continue
+		}
+		t.AppendRow(table.Row{d.Mountpoint, d.InodesTotal, d.InodesFree, d.InodesUsed, percentCell(d.InodesUsedPercent, d.InodesTotal)})
+		rows++
+	}
+
+	if rows == 0 {
+		return nil
+	}
+	return []*titledTable{t}
+}
+
+// disk I/O counters, with rates if they were sampled
+func diskIOTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	if !o.diskIO && len(m.DiskIO) == 0 && !failed[SubsystemDiskIO] {
+		return nil
+	}
+
+	rates := o.diskRateInterval > 0 || hasDiskRates(m.DiskIO)
+	header := []interface{}{"Device", "Read", "Written", "Reads", "Writes"}
+	if rates {
+		header = append(header, "Read/s", "Written/s", "Reads/s", "Writes/s")
+	}
+
+	t := newTable("Disk I/O", header...)
+	for _, name := range sortedKeys(m.DiskIO) {
+		c := m.DiskIO[name]
+		row := table.Row{name, o.humanReadable(c.ReadBytes), o.humanReadable(c.WriteBytes), c.ReadCount, c.WriteCount}
+		if rates {
+			row = append(row,
+				o.humanReadable(uint64(c.ReadBytesRate))+"/s",
+				o.humanReadable(uint64(c.WriteBytesRate))+"/s",
+				fmt.Sprintf("%.1f", c.ReadCountRate), fmt.Sprintf("%.1f", c.WriteCountRate))
+		}
+		t.AppendRow(row)
+	}
+
+	if failed[SubsystemDiskIO] {
+		t.AppendRow(m.unavailableRow(SubsystemDiskIO, len(header)))
+	}
+	return []*titledTable{t}
+}
+
+// hasDiskRates reports whether any of the counters has a non-zero rate,
+// for metrics read elsewhere with WithDiskRateInterval.
+func hasDiskRates(counters map[string]DiskIOCounters) bool {
+	for _, c := range counters {
+		if c.ReadBytesRate != 0 || c.WriteBytesRate != 0 || c.ReadCountRate != 0 || c.WriteCountRate != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// system memory usage
+func memoryTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	header := []interface{}{"#", "Total Memory", "Free Memory", "Used Memory", "Cache Memory", "Used %"}
+	t := newTable("System Memory", o.withBarHeader(header)...)
+	if failed[SubsystemMemory] {
+		t.AppendRow(m.unavailableRow(SubsystemMemory, len(o.withBarHeader(header))))
+	} else {
+		t.AppendRow(o.withBar(table.Row{
+			1, o.humanReadable(m.TotalMemory), o.humanReadable(m.FreeMemory), o.humanReadable(m.UsedMemory), o.humanReadable(m.CacheMemory),
+			percentCell(m.MemoryUsedPercent, m.TotalMemory),
+		}, m.MemoryUsedPercent))
+	}
+	if o.barWidth > 0 {
+		t.bar = len(header) + 1
+	}
+
+	t.usage, t.warning, t.critical = len(header), o.thresholds.MemoryWarningPercent, o.thresholds.MemoryPercent
+	return []*titledTable{t}
+}
+
+// memory of the Go runtime
+func goRuntimeTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	t := newTable("Go Runtime Memory", "Alloc", "Heap In Use", "Sys", "GC Cycles")
+	t.AppendRow(table.Row{
+		o.humanReadable(m.GoMemory.Alloc), o.humanReadable(m.GoMemory.HeapInuse),
+		o.humanReadable(m.GoMemory.Sys), m.GoMemory.NumGC,
+	})
+	return []*titledTable{t}
+}
+
+// hostname, platform, platform version, running processes, load and uptime
+func platformTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	hostValue := func(v interface{}) interface{} {
+		if failed[SubsystemHost] {
+			return m.unavailable(SubsystemHost)
+		}
+		return v
+	}
+
+	loadAvg := fmt.Sprintf("%.2f, %.2f, %.2f", m.LoadAvg.Load1, m.LoadAvg.Load5, m.LoadAvg.Load15)
+	if failed[SubsystemLoad] {
+		loadAvg = m.unavailable(SubsystemLoad)
+	}
+
+	t := newTable("System Info", "Property", "Value")
+	t.AppendRows([]table.Row{
+		{"Hostname", hostValue(m.Hostname)},
+		{"Running Processes", hostValue(m.RunningProcesses)},
+		{"Platform", hostValue(m.Platform)},
+		{"Platform Version", hostValue(m.PlatformVersion)},
+		{"Load Average", loadAvg},
+		{"Uptime", hostValue(formatDuration(m.Uptime))},
+		{"Boot Time", hostValue(m.BootTime.Format(time.RFC1123))},
+		{"GOOS/GOARCH", m.GOOS + "/" + m.GOARCH},
+		{"Go Version", m.GoVersion},
+		{"Goroutines", m.NumGoroutine},
+	})
+
+	if m.VirtSystem != "" {
+		t.AppendRow(table.Row{"Virtualization", strings.TrimSpace(m.VirtSystem + " " + m.VirtRole)})
+	}
+
+	switch {
+	case failed[SubsystemFileDescriptors]:
+		t.AppendRow(table.Row{"Open File Descriptors", m.unavailable(SubsystemFileDescriptors)})
+	case m.MaxFDs > 0:
+		t.AppendRow(table.Row{"Open File Descriptors", fmt.Sprintf("%d / %d", m.OpenFDs, m.MaxFDs)})
+	}
+
+	if m.InContainer || m.ContainerMemLimit > 0 || m.ContainerCPUQuota > 0 {
+		memLimit, cpuQuota := "unlimited", "unlimited"
+		if m.ContainerMemLimit > 0 {
+			memLimit = o.humanReadable(m.ContainerMemLimit)
+		}
+		if m.ContainerCPUQuota > 0 {
+			cpuQuota = fmt.Sprintf("%.2f cpus", m.ContainerCPUQuota)
+		}
+
+		t.AppendRows([]table.Row{
+			{"Container", m.InContainer},
+			{"Container Memory Limit", memLimit},
+			{"Container CPU Quota", cpuQuota},
+		})
+	}
+	return []*titledTable{t}
+}
+
+// vendor, product and firmware of the machine, if exposed
+func hardwareTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	if m.Hardware == (Hardware{}) {
+		return nil
+	}
+
+	t := newTable("Hardware", "Property", "Value")
+	for _, p := range []struct{ name, value string }{
+		{"Vendor", m.Hardware.Vendor},
+		{"Product", m.Hardware.Product},
+		{"Version", m.Hardware.Version},
+		{"Serial", m.Hardware.Serial},
+		{"BIOS Vendor", m.Hardware.BIOSVendor},
+		{"BIOS Version", m.Hardware.BIOSVersion},
+		{"BIOS Date", m.Hardware.BIOSDate},
+	} {
+		if p.value != "" {
+			t.AppendRow(table.Row{p.name, p.value})
+		}
+	}
+	return []*titledTable{t}
+}
+
+// usage of the NVIDIA GPUs, if read
+func gpuTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	if len(m.GPUs) == 0 && !failed[SubsystemGPU] {
+		return nil
+	}
+
+	t := newTable("GPUs", "GPU", "Name", "Utilization", "Memory Used", "Memory Total", "Temperature")
+	for _, g := range m.GPUs {
+		t.AppendRow(table.Row{
+			g.Index, g.Name, fmt.Sprintf("%.0f%%", g.UtilizationPercent),
+			o.humanReadable(g.MemoryUsed), o.humanReadable(g.MemoryTotal), o.temperature(g.Temperature),
+		})
+	}
+
+	if failed[SubsystemGPU] {
+		t.AppendRow(m.unavailableRow(SubsystemGPU, 6))
+	}
+	return []*titledTable{t}
+}
+
+// sensor temperatures, if any
+func temperatureTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	if len(m.Temperatures) == 0 && !failed[SubsystemTemperatures] {
+		return nil
+	}
+
+	t := newTable("Temperatures", "Sensor", "Temperature", "High", "Critical")
+	// sensors without a high or critical limit report zero
+	limit := func(c float64) string {
+		if c == 0 {
+			return "N/A"
+		}
+		return o.temperature(c)
+	}
+
+	for _, temp := range m.Temperatures {
+		t.AppendRow(table.Row{temp.SensorKey, o.temperature(temp.Temperature), limit(temp.High), limit(temp.Critical)})
+	}
+
+	if failed[SubsystemTemperatures] {
+		t.AppendRow(m.unavailableRow(SubsystemTemperatures, 4))
+	}
+	return []*titledTable{t}
+}
+
+// battery status, if any
+func batteryTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	if len(m.Batteries) == 0 {
+		return nil
+	}
+
+	t := newTable("Battery", "Battery", "Charge", "State", "Time Remaining")
+	for _, b := range m.Batteries {
+		remaining := "unknown"
+		if b.TimeRemaining > 0 {
+			remaining = formatDuration(b.TimeRemaining)
+		}
+		t.AppendRow(table.Row{b.Name, fmt.Sprintf("%.0f%%", b.Percent), b.State, remaining})
+	}
+	return []*titledTable{t}
+}
+
+// the top processes by cpu and memory
+func processTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	processRow := func(pid interface{}, p ProcessInfo) table.Row {
+		return table.Row{
+			pid, p.Name, fmt.Sprintf("%.2f%%", p.CPUPercent),
+			fmt.Sprintf("%.2f%%", p.MemoryPercent), o.humanReadable(p.RSS),
+		}
+	}
+
+	var tables []*titledTable
+	if o.topProcesses > 0 || len(m.TopCPUProcesses) > 0 || len(m.TopMemoryProcesses) > 0 {
+		for _, top := range []struct {
+			title string
+			procs []ProcessInfo
+		}{
+			{"Top Processes by CPU", m.TopCPUProcesses},
+			{"Top Processes by Memory", m.TopMemoryProcesses},
+		} {
+			t := newTable(top.title, "PID", "Name", "CPU %", "Memory %", "RSS")
+			for _, p := range top.procs {
+				t.AppendRow(processRow(p.PID, p))
+			}
+
+			if failed[SubsystemProcesses] {
+				t.AppendRow(m.unavailableRow(SubsystemProcesses, 5))
+			}
+			tables = append(tables, t)
+		}
+	}
+
+	if o.processFilter != nil || len(m.MatchedProcesses) > 0 {
+		title := "Processes Matching"
+		if o.processFilter != nil {
+			title += " " + o.processFilter.String()
+		}
+
+		t := newTable(title, "PID", "Name", "CPU %", "Memory %", "RSS")
+		for _, p := range m.MatchedProcesses {
+			t.AppendRow(processRow(p.PID, p))
+		}
+
+		if failed[SubsystemProcesses] {
+			t.AppendRow(m.unavailableRow(SubsystemProcesses, 5))
+		} else {
+			total := m.MatchedProcessesTotal
+			total.Name = fmt.Sprintf("%d processes", len(m.MatchedProcesses))
+			t.AppendRow(processRow("Total", total))
+		}
+		tables = append(tables, t)
+	}
+	return tables
+}
+
+// network interfaces, mac and IP addresses
+func networkTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	t := newTable("Network Interfaces", "Interface", "MAC Address", "IPv4 Addresses", "IPv6 Addresses", "Speed", "MTU")
+
+	for _, iface := range m.interfaceNames() {
+		speed, mtu := "unknown", "unknown"
+		if s, ok := m.LinkSpeeds[iface]; ok {
+			speed = fmt.Sprintf("%d Mb/s", s)
+		}
+		if v, ok := m.MTUs[iface]; ok {
+			mtu = strconv.Itoa(v)
+		}
+
+		t.AppendRows([]table.Row{
+			{iface, m.MacAddrs[iface], o.joinAddrs(m.IPv4Addrs[iface]), o.joinAddrs(m.IPv6Addrs[iface]), speed, mtu},
+		})
+	}
+
+	if failed[SubsystemNetwork] {
+		t.AppendRow(m.unavailableRow(SubsystemNetwork, 6))
+	}
+
+	gateway, dns := m.DefaultGateway, strings.Join(m.DNSServers, ", ")
+	if failed[SubsystemGateway] {
+		gateway = m.unavailable(SubsystemGateway)
+	}
+	if failed[SubsystemDNS] {
+		dns = m.unavailable(SubsystemDNS)
+	}
+
+	primaryIP := m.PrimaryIP
+	if failed[SubsystemGateway] {
+		primaryIP = m.unavailable(SubsystemGateway)
+	}
+
+	tr := newTable("Routing", "Property", "Value")
+	tr.AppendRows([]table.Row{
+		{"Primary IP", primaryIP},
+		{"Default Gateway", gateway},
+		{"DNS Servers", dns},
+	})
+	return []*titledTable{t, tr}
+}
+
+// network I/O counters, with rates if they were sampled
+func netIOTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	rates := o.netRateInterval > 0 || hasNetRates(m.NetIO)
+	header := []interface{}{"Interface", "Bytes Sent", "Bytes Recv", "Packets Sent", "Packets Recv", "Errors In/Out", "Drops In/Out"}
+	if rates {
+		header = append(header, "Sent/s", "Recv/s")
+	}
+
+	t := newTable("Network I/O", header...)
+
+	for _, iface := range sortedKeys(m.NetIO) {
+		c := m.NetIO[iface]
+		row := table.Row{
+			iface, o.humanReadable(c.BytesSent), o.humanReadable(c.BytesRecv),
+			c.PacketsSent, c.PacketsRecv,
+			fmt.Sprintf("%d/%d", c.Errin, c.Errout), fmt.Sprintf("%d/%d", c.Dropin, c.Dropout),
+		}
+
+		if rates {
+			row = append(row,
+				o.humanReadable(uint64(c.BytesSentRate))+"/s",
+				o.humanReadable(uint64(c.BytesRecvRate))+"/s")
+		}
+		t.AppendRow(row)
+	}
+
+	if failed[SubsystemNetIO] {
+		t.AppendRow(m.unavailableRow(SubsystemNetIO, len(header)))
+	} else if len(m.NetIO) > 1 {
+		t.AppendRow(table.Row{"Total", o.humanReadable(m.TotalBytesSent), o.humanReadable(m.TotalBytesRecv)})
+	}
+	return []*titledTable{t}
+}
+
+// hasNetRates reports whether any of the counters has a non-zero rate,
+// for metrics read elsewhere with WithNetRateInterval.
+func hasNetRates(counters map[string]NetIOCounters) bool {
+	for _, c := range counters {
+		if c.BytesSentRate != 0 || c.BytesRecvRate != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// network I/O of the processes by namespace, if read
+func processNetIOTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	if !o.processNetIO && len(m.ProcessNetIO) == 0 && !failed[SubsystemProcessNetIO] {
+		return nil
+	}
+
+	rates := o.netRateInterval > 0
+	for _, n := range m.ProcessNetIO {
+		rates = rates || n.BytesSentRate != 0 || n.BytesRecvRate != 0
+	}
+
+	header := []interface{}{"Namespace", "Processes", "Bytes Sent", "Bytes Recv"}
+	if rates {
+		header = append(header, "Sent/s", "Recv/s")
+	}
+
+	t := newTable("Network I/O by Process", header...)
+	for _, n := range m.ProcessNetIO {
+		row := table.Row{n.Namespace, summarizeNames(n.Processes, 3), o.humanReadable(n.BytesSent), o.humanReadable(n.BytesRecv)}
+		if rates {
+			row = append(row,
+				o.humanReadable(uint64(n.BytesSentRate))+"/s",
+				o.humanReadable(uint64(n.BytesRecvRate))+"/s")
+		}
+		t.AppendRow(row)
+	}
+
+	if failed[SubsystemProcessNetIO] {
+		t.AppendRow(m.unavailableRow(SubsystemProcessNetIO, len(header)))
+	}
+	return []*titledTable{t}
+}
+
+// summarizeNames lists the first n distinct names, sorted,
+// followed by the number of processes, e.g. "nginx, sshd (12)".
+func summarizeNames(names []string, n int) string {
+	seen := make(map[string]bool)
+	for _, name := range names {
+		seen[name] = true
+	}
+
+	distinct := sortedKeys(seen)
+	if len(distinct) > n {
+		distinct = append(distinct[:n], "...")
+	}
+	return fmt.Sprintf("%s (%d)", strings.Join(distinct, ", "), len(names))
+}
+
+// tcp connections by state, if read
+func connectionTables(m Metrics, failed map[string]bool, o *options) []*titledTable {
+	if !o.connections && len(m.Connections) == 0 && !failed[SubsystemConnections] {
+		return nil
+	}
+
+	t := newTable("TCP Connections", "State", "Count")
+	for _, state := range sortedKeys(m.Connections) {
+		t.AppendRow(table.Row{state, m.Connections[state]})
+	}
+
+	if failed[SubsystemConnections] {
+		t.AppendRow(m.unavailableRow(SubsystemConnections, 2))
+	}
+	return []*titledTable{t}
+}
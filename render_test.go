@@ -0,0 +1,201 @@
+package gonet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden files in testdata")
+
+// testMetrics returns metrics with every section filled, for golden files.
+func testMetrics() Metrics {
+	return Metrics{
+		SchemaVersion: SchemaVersion,
+		CollectedAt:   time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+
+		DiskPath:        "/",
+		DiskSize:        500 << 30,
+		DiskFree:        380 << 30,
+		DiskUsage:       120 << 30,
+		DiskUsedPercent: 24,
+		Disks: []DiskUsage{
+			{Mountpoint: "/", Fstype: "ext4", Total: 500 << 30, Free: 380 << 30, Used: 120 << 30, UsedPercent: 24,
+				InodesTotal: 32000000, InodesFree: 30000000, InodesUsed: 2000000, InodesUsedPercent: 6.25},
+			{Mountpoint: "/boot", Fstype: "vfat", Total: 512 << 20, Free: 64 << 20, Used: 448 << 20, UsedPercent: 87.5},
+		},
+		DiskIO: map[string]DiskIOCounters{
+			"sda":     {ReadBytes: 3 << 30, WriteBytes: 1 << 30, ReadCount: 120000, WriteCount: 45000, ReadBytesRate: 1 << 20, WriteBytesRate: 512 << 10},
+			"nvme0n1": {ReadBytes: 9 << 30, WriteBytes: 4 << 30, ReadCount: 800000, WriteCount: 300000},
+		},
+
+		TotalMemory:       16 << 30,
+		FreeMemory:        4 << 30,
+		UsedMemory:        10 << 30,
+		CacheMemory:       2 << 30,
+		MemoryUsedPercent: 62.5,
+
+		GoNumCPU: 4,
+		CPUInfo: []CPUInfo{
+			{Index: 0, VendorID: "GenuineIntel", Family: "6", Cores: 4, Model: "Intel(R) Core(TM) i5-8250U CPU @ 1.60GHz", Speed: "1800.00 MHz"},
+		},
+		CPUPercent:     12.5,
+		LogicalCores:   4,
+		PhysicalCores:  2,
+		CPUTimes:       &CPUTimes{User: 8, System: 4, Idle: 86, IOWait: 1.5, Steal: 0.5},
+		PerCorePercent: []float64{10, 20, 5, 15},
+
+		Hostname:         "web01",
+		RunningProcesses: 312,
+		Platform:         "ubuntu",
+		PlatformVersion:  "22.04",
+		LoadAvg:          LoadAvg{Load1: 0.52, Load5: 0.61, Load15: 0.7},
+		VirtSystem:       "kvm",
+		VirtRole:         "guest",
+		GPUs:             []GPU{{Index: 0, Name: "NVIDIA T4", UtilizationPercent: 35, MemoryUsed: 2 << 30, MemoryTotal: 16 << 30, Temperature: 48}},
+		Hardware:         Hardware{Vendor: "LENOVO", Product: "20L5", Version: "ThinkPad T480", Serial: "PF0ABCDE", BIOSVendor: "LENOVO", BIOSVersion: "N24ET61W", BIOSDate: "05/11/2020"},
+		Uptime:           (3*24+4)*time.Hour + 12*time.Minute,
+		BootTime:         time.Date(2024, 2, 26, 8, 18, 0, 0, time.UTC),
+
+		GOOS:      "linux",
+		GOARCH:    "amd64",
+		GoVersion: "go1.20",
+
+		OpenFDs: 12,
+		MaxFDs:  1024,
+
+		MacAddr:        "aa:bb:cc:dd:ee:01",
+		MacAddrs:       map[string]string{"eth0": "aa:bb:cc:dd:ee:01", "wlan0": "aa:bb:cc:dd:ee:02"},
+		IPAddrs:        map[string][]string{"eth0": {"192.168.1.10/24", "fe80::1/64"}, "lo": {"127.0.0.1/8", "::1/128"}},
+		IPv4Addrs:      map[string][]string{"eth0": {"192.168.1.10/24"}, "lo": {"127.0.0.1/8"}},
+		IPv6Addrs:      map[string][]string{"eth0": {"fe80::1/64"}, "lo": {"::1/128"}},
+		MTUs:           map[string]int{"eth0": 1500, "lo": 65536, "wlan0": 1500},
+		LinkSpeeds:     map[string]int{"eth0": 1000},
+		DefaultGateway: "192.168.1.1",
+		DNSServers:     []string{"1.1.1.1", "8.8.8.8"},
+		PrimaryIP:      "192.168.1.10",
+		NetIO: map[string]NetIOCounters{
+			"eth0": {BytesSent: 300 << 20, BytesRecv: 2 << 30, PacketsSent: 200000, PacketsRecv: 1500000, BytesSentRate: 10 << 10, BytesRecvRate: 200 << 10},
+			"lo":   {BytesSent: 5 << 20, BytesRecv: 5 << 20, PacketsSent: 4000, PacketsRecv: 4000},
+		},
+		TotalBytesSent: 305 << 20,
+		TotalBytesRecv: 2<<30 + 5<<20,
+		Connections:    map[string]int{"ESTABLISHED": 14, "LISTEN": 6, "TIME_WAIT": 3},
+
+		Temperatures: []Temperature{{SensorKey: "coretemp_package_id_0", Temperature: 52, High: 100, Critical: 100}, {SensorKey: "acpitz", Temperature: 40}},
+		Batteries:    []Battery{{Name: "BAT0", Percent: 81.5, State: "discharging", TimeRemaining: 2*time.Hour + 45*time.Minute}},
+
+		TopCPUProcesses:    []ProcessInfo{{PID: 1200, Name: "postgres", CPUPercent: 8.5, MemoryPercent: 6.25, RSS: 1 << 30}, {PID: 1, Name: "systemd", CPUPercent: 0.1, MemoryPercent: 0.1, RSS: 12 << 20}},
+		TopMemoryProcesses: []ProcessInfo{{PID: 1200, Name: "postgres", CPUPercent: 8.5, MemoryPercent: 6.25, RSS: 1 << 30}},
+
+		ProcessNetIO: []ProcessNetIO{{Namespace: "net:[4026531840]", PIDs: []int32{1, 1200}, Processes: []string{"systemd", "postgres"}, BytesSent: 305 << 20, BytesRecv: 2 << 30, BytesSentRate: 10 << 10, BytesRecvRate: 200 << 10}},
+	}
+}
+
+// checkGolden compares got with the golden file testdata/name.golden,
+// or replaces the file with got when run with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v; run go test -update to create it", err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s, run go test -update if the change is intended\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestRenderMetricsGolden(t *testing.T) {
+	unavailable := testMetrics()
+	unavailable.Errors = SubsystemErrors{
+		SubsystemMemory:      errors.New("no meminfo"),
+		SubsystemBattery:     errors.New("no battery"),
+		SubsystemConnections: os.ErrPermission,
+	}
+
+	tests := []struct {
+		name    string
+		metrics Metrics
+		opts    []Option
+	}{
+		{"tables", testMetrics(), nil},
+		{"unavailable", unavailable, nil},
+		{"decimal", testMetrics(), []Option{WithUnits(UnitsDecimal), WithPrecision(1)}},
+		{"sections", testMetrics(), []Option{WithSections(SectionMemory, SectionDisk)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := append([]Option{WithNoColor()}, tt.opts...)
+			if err := RenderMetrics(&buf, tt.metrics, opts...); err != nil {
+				t.Fatal(err)
+			}
+			checkGolden(t, "render_"+tt.name, buf.Bytes())
+		})
+	}
+}
+
+func TestPercentCell(t *testing.T) {
+	tests := []struct {
+		p     float64
+		total uint64
+		want  string
+	}{
+		{0, 0, "N/A"},
+		{math.NaN(), 0, "N/A"},
+		{percent(0, 0), 0, "N/A"},
+		{0, 100, "0.0%"},
+		{42.25, 100, "42.2%"},
+		{100, 100, "100.0%"},
+	}
+
+	for _, tt := range tests {
+		if got := percentCell(tt.p, tt.total); got != tt.want {
+			t.Errorf("percentCell(%v, %d) = %q, want %q", tt.p, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestRenderMetricsDecodedJSON(t *testing.T) {
+	var want bytes.Buffer
+	if err := RenderMetrics(&want, testMetrics(), WithNoColor()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the optional sections and rate columns don't need the read options
+	var encoded bytes.Buffer
+	if err := writeJSON(&encoded, testMetrics()); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Metrics
+	if err := json.Unmarshal(encoded.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	var got bytes.Buffer
+	if err := RenderMetrics(&got, decoded, WithNoColor()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.String() != want.String() {
+		t.Errorf("decoded metrics render differently\ngot:\n%s\nwant:\n%s", got.String(), want.String())
+	}
+}
@@ -0,0 +1,53 @@
+package gonet
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/host"
+)
+
+// Temperature holds the reading of a temperature sensor in degrees Celsius.
+type Temperature struct {
+	SensorKey   string  `json:"sensor_key" yaml:"sensor_key"`
+	Temperature float64 `json:"temperature" yaml:"temperature"`
+	High        float64 `json:"high" yaml:"high"`
+	Critical    float64 `json:"critical" yaml:"critical"`
+}
+
+// celsiusToFahrenheit converts a temperature in degrees Celsius to Fahrenheit.
+func celsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+// temperature formats c, in degrees Celsius, in the unit of the tables.
+func (o *options) temperature(c float64) string {
+	if o.fahrenheit {
+		return fmt.Sprintf("%.1f °F", celsiusToFahrenheit(c))
+	}
+	return fmt.Sprintf("%.1f °C", c)
+}
+
+// getTemperatures returns the readings of the host temperature sensors.
+// Sensors are often not exposed, e.g. in containers and virtual machines,
+// so failures are not reported and give no readings, unless no sensor
+// could be read for lack of privileges.
+func getTemperatures(ctx context.Context) ([]Temperature, error) {
+	// gopsutil returns the sensors it could read along with
+	// warnings for the ones it could not.
+	stats, err := withContext(ctx, host.SensorsTemperaturesWithContext)
+	if len(stats) == 0 && IsPermissionError(err) {
+		return nil, err
+	}
+
+	var temps []Temperature
+	for _, t := range stats {
+		temps = append(temps, Temperature{
+			SensorKey:   t.SensorKey,
+			Temperature: t.Temperature,
+			High:        t.High,
+			Critical:    t.Critical,
+		})
+	}
+	return temps, nil
+}
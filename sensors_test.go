@@ -0,0 +1,42 @@
+package gonet
+
+import "testing"
+
+func TestCelsiusToFahrenheit(t *testing.T) {
+	tests := []struct {
+		c, want float64
+	}{
+		{-40, -40},
+		{-17.5, 0.5},
+		{0, 32},
+		{37, 98.6},
+		{100, 212},
+	}
+
+	for _, tt := range tests {
+		if got := celsiusToFahrenheit(tt.c); got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("celsiusToFahrenheit(%v) = %v, want %v", tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestTemperature(t *testing.T) {
+	tests := []struct {
+		c          float64
+		fahrenheit bool
+		want       string
+	}{
+		{-40, false, "-40.0 °C"},
+		{-40, true, "-40.0 °F"},
+		{0, true, "32.0 °F"},
+		{100, false, "100.0 °C"},
+		{100, true, "212.0 °F"},
+	}
+
+	for _, tt := range tests {
+		o := newOptions([]Option{WithFahrenheit(tt.fahrenheit)})
+		if got := o.temperature(tt.c); got != tt.want {
+			t.Errorf("temperature(%v) with fahrenheit %v = %q, want %q", tt.c, tt.fahrenheit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package gonet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// remoteCommand is the command run by ReadMetricsSSH on the remote host.
+const remoteCommand = "gonet -json"
+
+// ReadMetricsSSH reads the metrics of the remote host at addr ("host" or
+// "host:port", port 22 by default) by running gonet -json over SSH and
+// parsing its output. The gonet command must be in the PATH of the remote user.
+//
+// If some subsystems could not be read on the remote host, the metrics are
+// returned with an error holding the error output of the remote command.
+// Closing ctx aborts the connection.
+func ReadMetricsSSH(ctx context.Context, addr string, cfg *ssh.ClientConfig) (Metrics, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return Metrics{}, err
+	}
+
+	// close the connection when ctx is done to unblock the handshake and session
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-stop:
+		}
+	}()
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, cfg)
+	if err != nil {
+		conn.Close()
+		return Metrics{}, contextError(ctx, err)
+	}
+	client := ssh.NewClient(c, chans, reqs)
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return Metrics{}, contextError(ctx, err)
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	if err := session.Run(remoteCommand); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return Metrics{}, contextError(ctx, err)
+	}
+
+	var m Metrics
+	if err := json.Unmarshal(stdout.Bytes(), &m); err != nil {
+		return Metrics{}, fmt.Errorf("parsing output of %q: %w", remoteCommand, err)
+	}
+
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return m, errors.New(msg)
+	}
+	return m, nil
+}
+
+// contextError returns the error of ctx if it is done, as it caused err.
+func contextError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
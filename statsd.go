@@ -0,0 +1,120 @@
+package gonet
+
+import (
+	"net"
+	"strconv"
+	"strings"
+)
+
+// statsdPacketSize is the largest UDP payload sent to StatsD, which
+// fits in a single packet on common networks.
+const statsdPacketSize = 1432
+
+// PushStatsD reads metrics and sends them as gauges to the StatsD server
+// at addr, e.g. "localhost:8125", over UDP. Every name starts with prefix,
+// e.g. "web01" gives web01.cpu.percent and web01.disk.root.used_percent.
+// The gauges are the cpu usage, the memory and disk usage, and the
+// cumulative network I/O counters of each interface. Mountpoints and
+// interface names are written with dots, slashes and StatsD delimiters
+// replaced by underscores, "/" as root.
+//
+// Metrics of subsystems that could not be read are left out, and the
+// rest is sent, in which case the collection error from ReadMetrics is
+// returned unless sending failed.
+func PushStatsD(addr, prefix string, opts ...Option) error {
+	opts = append([]Option{WithTopProcesses(0)}, opts...)
+	metrics, err := ReadMetrics(opts...)
+
+	conn, derr := net.Dial("udp", addr)
+	if derr != nil {
+		return derr
+	}
+	defer conn.Close()
+
+	if werr := sendStatsD(conn, statsdGauges(metrics, failedSubsystems(err), prefix)); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// sendStatsD sends lines to conn, as many per packet as fit.
+func sendStatsD(conn net.Conn, lines []string) error {
+	var packet []byte
+	for _, line := range lines {
+		if len(packet) > 0 && len(packet)+1+len(line) > statsdPacketSize {
+			if _, err := conn.Write(packet); err != nil {
+				return err
+			}
+			packet = packet[:0]
+		}
+
+		if len(packet) > 0 {
+			packet = append(packet, '\n')
+		}
+		packet = append(packet, line...)
+	}
+
+	if len(packet) > 0 {
+		_, err := conn.Write(packet)
+		return err
+	}
+	return nil
+}
+
+// statsdGauges returns the StatsD gauge lines of m, e.g. prefix.cpu.percent:12.5|g.
+func statsdGauges(m Metrics, failed map[string]bool, prefix string) []string {
+	prefix = strings.TrimSuffix(prefix, ".")
+	if prefix != "" {
+		prefix += "."
+	}
+
+	var lines []string
+	gauge := func(name string, v float64) {
+		lines = append(lines, prefix+name+":"+strconv.FormatFloat(v, 'f', -1, 64)+"|g")
+	}
+
+	if !failed[SubsystemCPUPercent] {
+		gauge("cpu.percent", m.CPUPercent)
+	}
+
+	if !failed[SubsystemMemory] {
+		gauge("memory.total", float64(m.TotalMemory))
+		gauge("memory.used", float64(m.UsedMemory))
+		gauge("memory.free", float64(m.FreeMemory))
+		gauge("memory.used_percent", m.MemoryUsedPercent)
+	}
+
+	if !failed[SubsystemPartitions] {
+		for _, d := range m.Disks {
+			name := "disk." + statsdName(d.Mountpoint)
+			gauge(name+".total", float64(d.Total))
+			gauge(name+".used", float64(d.Used))
+			gauge(name+".free", float64(d.Free))
+			gauge(name+".used_percent", d.UsedPercent)
+		}
+	}
+
+	if !failed[SubsystemNetIO] {
+		for _, iface := range sortedKeys(m.NetIO) {
+			c := m.NetIO[iface]
+			name := "net." + statsdName(iface)
+			gauge(name+".bytes_sent", float64(c.BytesSent))
+			gauge(name+".bytes_recv", float64(c.BytesRecv))
+			gauge(name+".packets_sent", float64(c.PacketsSent))
+			gauge(name+".packets_recv", float64(c.PacketsRecv))
+		}
+	}
+	return lines
+}
+
+// statsdReplacer replaces the characters that can't be part of a name segment.
+var statsdReplacer = strings.NewReplacer(".", "_", "/", "_", `\`, "_", ":", "_", "|", "_", "@", "_", " ", "_")
+
+// statsdName returns s as a single segment of a StatsD name, "root" for "/".
+func statsdName(s string) string {
+	s = strings.Trim(s, `/\`)
+	if s == "" {
+		return "root"
+	}
+	return statsdReplacer.Replace(s)
+}
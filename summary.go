@@ -0,0 +1,61 @@
+package gonet
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jedib0t/go-pretty/table"
+)
+
+// WriteSummary writes a compact summary of the metrics to the given writer
+// as a single table: cpu, memory and disk usage, network traffic, load,
+// uptime, hostname and primary IP.
+// If writer is nil, it will write to stdout
+//
+// Processes are not listed unless asked for with WithTopProcesses.
+// Like WriteMetrics, it returns the write error, or else the collection error.
+func WriteSummary(writer io.Writer, opts ...Option) error {
+	if writer == nil {
+		writer = os.Stdout
+	}
+
+	opts = append([]Option{WithTopProcesses(0)}, opts...)
+	metrics, err := ReadMetrics(opts...)
+	o := newOptions(opts)
+
+	t := summaryTable(metrics, failedSubsystems(err), o)
+	t.localize(o.locale)
+	t.SetStyle(o.tableStyle(writer, table.StyleColoredBright))
+	t.fit(o.tableWidth(writer))
+	if _, werr := fmt.Fprintln(writer, t.Render()); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// summaryTable returns the key metrics of m as a single table.
+func summaryTable(m Metrics, failed map[string]bool, o *options) *titledTable {
+	value := func(subsystem string, format string, a ...interface{}) string {
+		if failed[subsystem] {
+			return m.unavailable(subsystem)
+		}
+		return fmt.Sprintf(format, a...)
+	}
+
+	t := newTable("Summary", "Metric", "Value")
+	t.AppendRows([]table.Row{
+		{"CPU", value(SubsystemCPUPercent, "%.1f%%", m.CPUPercent)},
+		{"Memory", value(SubsystemMemory, "%s / %s (%s)",
+			o.humanReadable(m.UsedMemory), o.humanReadable(m.TotalMemory), percentCell(m.MemoryUsedPercent, m.TotalMemory))},
+		{"Disk " + m.DiskPath, value(SubsystemDisk, "%s / %s (%s)",
+			o.humanReadable(m.DiskUsage), o.humanReadable(m.DiskSize), percentCell(m.DiskUsedPercent, m.DiskSize))},
+		{"Network", value(SubsystemNetIO, "%s sent, %s received",
+			o.humanReadable(m.TotalBytesSent), o.humanReadable(m.TotalBytesRecv))},
+		{"Load", value(SubsystemLoad, "%.2f, %.2f, %.2f", m.LoadAvg.Load1, m.LoadAvg.Load5, m.LoadAvg.Load15)},
+		{"Uptime", value(SubsystemHost, "%s", formatDuration(m.Uptime))},
+		{"Hostname", value(SubsystemHost, "%s", m.Hostname)},
+		{"IP", value(SubsystemGateway, "%s", m.PrimaryIP)},
+	})
+	return t
+}
@@ -0,0 +1,33 @@
+//go:build linux
+
+package gonet
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// readSysString returns the trimmed content of the file name in dir,
+// or an empty string if it can't be read.
+func readSysString(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readSysUint returns the integer in the file name in dir, or 0.
+func readSysUint(dir, name string) uint64 {
+	v, _ := strconv.ParseUint(readSysString(dir, name), 10, 64)
+	return v
+}
+
+// readSysFloat returns the number in the file name in dir
+// and whether it could be read.
+func readSysFloat(dir, name string) (float64, bool) {
+	v, err := strconv.ParseFloat(readSysString(dir, name), 64)
+	return v, err == nil
+}
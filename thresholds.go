@@ -0,0 +1,144 @@
+package gonet
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Thresholds are the usage limits checked by Metrics.CheckThresholds,
+// as percentages, e.g. CPUPercent: 90. A zero limit is not checked.
+type Thresholds struct {
+	CPUPercent    float64 `json:"cpu_percent" yaml:"cpu_percent"`
+	MemoryPercent float64 `json:"memory_percent" yaml:"memory_percent"`
+
+	// Checked against every mounted filesystem
+	DiskPercent float64 `json:"disk_percent" yaml:"disk_percent"`
+
+	// Optional lower limits, whose breaches are warnings rather than critical
+	CPUWarningPercent    float64 `json:"cpu_warning_percent" yaml:"cpu_warning_percent"`
+	MemoryWarningPercent float64 `json:"memory_warning_percent" yaml:"memory_warning_percent"`
+	DiskWarningPercent   float64 `json:"disk_warning_percent" yaml:"disk_warning_percent"`
+}
+
+// Breach is a metric above its limit.
+type Breach struct {
+	// cpu, memory or disk:<mountpoint>, e.g. disk:/home
+	Metric string  `json:"metric" yaml:"metric"`
+	Limit  float64 `json:"limit" yaml:"limit"`
+	Value  float64 `json:"value" yaml:"value"`
+
+	// Critical is false if only the warning limit was breached.
+	Critical bool `json:"critical" yaml:"critical"`
+}
+
+func (b Breach) String() string {
+	return fmt.Sprintf("%s at %.1f%% is above %.1f%%", b.Metric, b.Value, b.Limit)
+}
+
+// CheckThresholds returns the metrics of m above their limit in t,
+// or above their warning limit if they are not above the limit.
+func (m Metrics) CheckThresholds(t Thresholds) []Breach {
+	var breaches []Breach
+	check := func(metric string, limit, warning, value float64) {
+		switch {
+		case limit > 0 && value > limit:
+			breaches = append(breaches, Breach{metric, limit, value, true})
+		case warning > 0 && value > warning:
+			breaches = append(breaches, Breach{metric, warning, value, false})
+		}
+	}
+
+	check("cpu", t.CPUPercent, t.CPUWarningPercent, m.CPUPercent)
+	check("memory", t.MemoryPercent, t.MemoryWarningPercent, m.MemoryUsedPercent)
+
+	// the disk of DiskPath is checked even if the partitions could not be read
+	check("disk:"+m.DiskPath, t.DiskPercent, t.DiskWarningPercent, m.DiskUsedPercent)
+	for _, d := range m.Disks {
+		if d.Mountpoint != m.DiskPath {
+			check("disk:"+d.Mountpoint, t.DiskPercent, t.DiskWarningPercent, d.UsedPercent)
+		}
+	}
+	return breaches
+}
+
+// alertingSections returns the set of sections showing a metric of m
+// above its limit or warning limit in t.
+func (m Metrics) alertingSections(t Thresholds) map[Section]bool {
+	alerting := make(map[Section]bool)
+	for _, b := range m.CheckThresholds(t) {
+		switch {
+		case b.Metric == "cpu":
+			alerting[SectionCPU] = true
+		case b.Metric == "memory":
+			alerting[SectionMemory] = true
+		case strings.HasPrefix(b.Metric, "disk:"):
+			alerting[SectionDisk] = true
+		}
+	}
+	return alerting
+}
+
+// breached returns the set of metrics of m above their limit in t,
+// leaving out warnings.
+func (m Metrics) breached(t Thresholds) map[string]bool {
+	breached := make(map[string]bool)
+	for _, b := range m.CheckThresholds(t) {
+		if b.Critical {
+			breached[b.Metric] = true
+		}
+	}
+	return breached
+}
+
+// Exit codes returned by RunCheck, following the Nagios plugin conventions.
+const (
+	CheckOK       = 0 // no metric is above its limits
+	CheckWarning  = 1 // some metrics are above their warning limit only
+	CheckCritical = 2 // some metrics are above their limit
+	CheckUnknown  = 3 // a checked metric could not be read
+)
+
+// RunCheck reads the metrics, checks them against t and returns an exit
+// code for monitoring systems that interpret them, e.g. os.Exit(gonet.RunCheck(t)).
+// A summary is written to stdout, e.g.
+//
+//	CRITICAL - disk:/var at 97.2% is above 95.0%
+//
+// Processes are not listed unless asked for with WithTopProcesses.
+func RunCheck(t Thresholds, opts ...Option) int {
+	return runCheck(os.Stdout, t, opts...)
+}
+
+func runCheck(w io.Writer, t Thresholds, opts ...Option) int {
+	opts = append([]Option{WithTopProcesses(0)}, opts...)
+	metrics, err := ReadMetrics(opts...)
+
+	failed := failedSubsystems(err)
+	for _, subsystem := range []string{SubsystemCPUPercent, SubsystemMemory, SubsystemDisk} {
+		if failed[subsystem] {
+			fmt.Fprintf(w, "UNKNOWN - %s\n", err)
+			return CheckUnknown
+		}
+	}
+
+	breaches := metrics.CheckThresholds(t)
+	if len(breaches) == 0 {
+		fmt.Fprintf(w, "OK - cpu %.1f%%, memory %.1f%%, disk %.1f%%\n",
+			metrics.CPUPercent, metrics.MemoryUsedPercent, metrics.DiskUsedPercent)
+		return CheckOK
+	}
+
+	code, status := CheckWarning, "WARNING"
+	summary := make([]string, len(breaches))
+	for i, b := range breaches {
+		if b.Critical {
+			code, status = CheckCritical, "CRITICAL"
+		}
+		summary[i] = b.String()
+	}
+
+	fmt.Fprintf(w, "%s - %s\n", status, strings.Join(summary, ", "))
+	return code
+}
@@ -0,0 +1,101 @@
+package gonet
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// WatchMetrics re-reads and writes metrics to the given writer every interval,
+// clearing the screen between frames, until ctx is done or a write fails.
+// If writer is nil, it will write to stdout.
+// WatchMetrics returns the write error, or the error of ctx once it is done.
+// An interval that is not positive is an error.
+// Subsystems that could not be read are marked unavailable in each frame.
+//
+// With the default zero cpu interval, each frame reports
+// the cpu usage since the previous frame, smoothed with WithCPUSmoothing.
+func WatchMetrics(ctx context.Context, writer io.Writer, interval time.Duration, opts ...Option) error {
+	if interval <= 0 {
+		return fmt.Errorf("gonet: watch interval must be positive, got %s", interval)
+	}
+
+	if writer == nil {
+		writer = os.Stdout
+	}
+
+	o := newOptions(opts)
+	smoother := &cpuSmoother{factor: o.cpuSmoothing}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := fmt.Fprint(writer, clearScreen); err != nil {
+			return err
+		}
+
+		metrics, err := ReadMetricsContext(ctx, opts...)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		failed := failedSubsystems(err)
+		if err := renderMetrics(writer, smoother.smooth(metrics, failed), failed, o); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// cpuSmoother keeps exponential moving averages of the cpu usage.
+type cpuSmoother struct {
+	factor  float64
+	total   float64
+	perCore []float64
+	started bool
+}
+
+// smooth returns m with its cpu usages replaced by their averages,
+// updated with the readings of m. The first reading starts the averages.
+// Failed readings are left as they are and don't update the averages.
+func (s *cpuSmoother) smooth(m Metrics, failed map[string]bool) Metrics {
+	if s.factor <= 0 || s.factor >= 1 {
+		return m
+	}
+
+	average := func(avg, v float64) float64 {
+		return s.factor*v + (1-s.factor)*avg
+	}
+
+	if !failed[SubsystemCPUPercent] {
+		if s.started {
+			s.total = average(s.total, m.CPUPercent)
+		} else {
+			s.total = m.CPUPercent
+		}
+		s.started = true
+		m.CPUPercent = s.total
+	}
+
+	if !failed[SubsystemPerCorePercent] {
+		if len(s.perCore) != len(m.PerCorePercent) {
+			s.perCore = append([]float64(nil), m.PerCorePercent...)
+		} else {
+			for i, v := range m.PerCorePercent {
+				s.perCore[i] = average(s.perCore[i], v)
+			}
+		}
+		m.PerCorePercent = append([]float64(nil), s.perCore...)
+	}
+	return m
+}
@@ -0,0 +1,26 @@
+package gonet
+
+import (
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WriteMetricsYAML writes metrics to the given writer as YAML,
+// using the same field names as the JSON output.
+// Byte counts are written as raw numbers.
+//
+// The metrics are written even if some subsystems could not be read,
+// in which case the collection error from ReadMetrics is returned.
+func WriteMetricsYAML(w io.Writer, opts ...Option) error {
+	return WriteMetricsAs(w, FormatYAML, opts...)
+}
+
+func writeYAML(w io.Writer, m Metrics) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return err
+	}
+	return enc.Close()
+}